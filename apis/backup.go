@@ -0,0 +1,106 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+)
+
+type Backup struct {
+	Name string `json:"name"`
+	Id   string `json:"id"`
+	Size int64  `json:"size"`
+
+	Incremental bool    `json:"incremental"`
+	BaseId      *string `json:"baseId,omitempty"`
+
+	Created int64 `json:"created"`
+}
+
+type GetBackups struct {
+	Backups []Backup `json:"backups"`
+}
+
+type CreateBackupBody struct {
+	Incremental bool `json:"incremental"`
+}
+
+func ConvertBackupInfo(info core.BackupInfo) Backup {
+	var baseId *string
+	if info.Manifest.Incremental() {
+		baseId = &info.Manifest.BaseId
+	}
+
+	return Backup{
+		Name:        info.Name,
+		Id:          info.Manifest.Id,
+		Size:        info.Size,
+		Incremental: info.Manifest.Incremental(),
+		BaseId:      baseId,
+		Created:     info.Manifest.Created,
+	}
+}
+
+func InstallBackupHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetBackups",
+			Method:       http.MethodGet,
+			Path:         "/system/backups",
+			ResponseType: GetBackups{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				backups, err := core.ListBackups(app.WorkDir().BackupsDir())
+				if err != nil {
+					return nil, err
+				}
+
+				res := GetBackups{
+					Backups: make([]Backup, len(backups)),
+				}
+
+				for i, backup := range backups {
+					res.Backups[i] = ConvertBackupInfo(backup)
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "CreateBackup",
+			Method:       http.MethodPost,
+			Path:         "/system/backups",
+			ResponseType: CreateJob{},
+			BodyType:     CreateBackupBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				body, err := pyrin.Body[CreateBackupBody](c)
+				if err != nil {
+					return nil, err
+				}
+
+				jobId, err := app.Jobs().Enqueue(context.TODO(), core.JobTypeBackup, core.BackupPayload{
+					Incremental: body.Incremental,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
+			},
+		},
+	)
+}
@@ -0,0 +1,154 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/types"
+)
+
+const (
+	defaultCalendarDays = 30
+	maxCalendarDays     = 366
+)
+
+type CalendarRelease struct {
+	CollectionId string `json:"collectionId"`
+	Title        string `json:"title"`
+	Part         int    `json:"part"`
+	Date         string `json:"date"`
+}
+
+type CalendarSeason struct {
+	Season   string            `json:"season"`
+	Releases []CalendarRelease `json:"releases"`
+}
+
+type GetCalendar struct {
+	Start   string           `json:"start"`
+	End     string           `json:"end"`
+	Seasons []CalendarSeason `json:"seasons"`
+}
+
+func parseCalendarRange(start, end string) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	startTime := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if start != "" {
+		t, err := time.Parse(types.MediaDateLayout, start)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid start date")
+		}
+
+		startTime = t
+	}
+
+	endTime := startTime.AddDate(0, 0, defaultCalendarDays)
+	if end != "" {
+		t, err := time.Parse(types.MediaDateLayout, end)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid end date")
+		}
+
+		// NOTE(patrik): The end date is inclusive
+		endTime = t.AddDate(0, 0, 1)
+	}
+
+	if !endTime.After(startTime) {
+		return time.Time{}, time.Time{}, errors.New("end date needs to be after the start date")
+	}
+
+	if endTime.Sub(startTime) > maxCalendarDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("date range is too large")
+	}
+
+	return startTime, endTime, nil
+}
+
+func InstallCalendarHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetCalendar",
+			Method:       http.MethodGet,
+			Path:         "/calendar",
+			ResponseType: GetCalendar{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				q := c.Request().URL.Query()
+
+				start, end, err := parseCalendarRange(q.Get("start"), q.Get("end"))
+				if err != nil {
+					return nil, InvalidFilter(err)
+				}
+
+				ctx := context.TODO()
+
+				collections, err := app.DB().GetCollectionsWithRelease(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				type release struct {
+					date time.Time
+					CalendarRelease
+				}
+
+				var releases []release
+
+				for _, collection := range collections {
+					schedule, ok := core.GetReleaseSchedule(collection)
+					if !ok {
+						continue
+					}
+
+					for _, r := range schedule.ReleasesBetween(start, end) {
+						releases = append(releases, release{
+							date: r.Date,
+							CalendarRelease: CalendarRelease{
+								CollectionId: collection.Id,
+								Title:        collection.Title,
+								Part:         r.Part,
+								Date:         r.Date.Format(types.MediaDateLayout),
+							},
+						})
+					}
+				}
+
+				sort.SliceStable(releases, func(i, j int) bool {
+					if releases[i].date.Equal(releases[j].date) {
+						return releases[i].Title < releases[j].Title
+					}
+
+					return releases[i].date.Before(releases[j].date)
+				})
+
+				res := GetCalendar{
+					Start:   start.Format(types.MediaDateLayout),
+					End:     end.AddDate(0, 0, -1).Format(types.MediaDateLayout),
+					Seasons: []CalendarSeason{},
+				}
+
+				for _, r := range releases {
+					season := types.GetAiringSeasonFromTime(r.date)
+
+					last := len(res.Seasons) - 1
+					if last < 0 || res.Seasons[last].Season != season {
+						res.Seasons = append(res.Seasons, CalendarSeason{
+							Season:   season,
+							Releases: []CalendarRelease{},
+						})
+						last++
+					}
+
+					res.Seasons[last].Releases = append(res.Seasons[last].Releases, r.CalendarRelease)
+				}
+
+				return res, nil
+			},
+		},
+	)
+}
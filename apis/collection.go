@@ -1,20 +1,19 @@
 package apis
 
 import (
-	"archive/zip"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"net/url"
-	"os"
 	"path"
-	"sort"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/maruel/natural"
 	"github.com/nanoteck137/pyrin"
 	"github.com/nanoteck137/pyrin/anvil"
 	"github.com/nanoteck137/storebook/core"
@@ -24,10 +23,33 @@ import (
 	"github.com/nanoteck137/validate"
 )
 
+type CollectionRelease struct {
+	Start        string `json:"start"`
+	DelayDays    int    `json:"delayDays"`
+	IntervalDays int    `json:"intervalDays"`
+	NumParts     int    `json:"numParts"`
+
+	Status      types.MediaPartReleaseStatus `json:"status"`
+	CurrentPart int                          `json:"currentPart"`
+	NextPart    *int                         `json:"nextPart,omitempty"`
+	NextRelease *string                      `json:"nextRelease,omitempty"`
+}
+
 type Collection struct {
 	Id string `json:"id"`
 
 	Title string `json:"title"`
+
+	Series *string `json:"series,omitempty"`
+	Volume *int64  `json:"volume,omitempty"`
+
+	// NOTE(patrik): Cover is missing when the collection has no images
+	// and no custom cover
+	Cover        *types.Images `json:"cover,omitempty"`
+	CoverImageId *string       `json:"coverImageId,omitempty"`
+	CustomCover  bool          `json:"customCover"`
+
+	Release *CollectionRelease `json:"release,omitempty"`
 }
 
 type GetCollection struct {
@@ -37,6 +59,9 @@ type GetCollection struct {
 
 type GetCollectionById struct {
 	Collection
+
+	Reader          ReaderSettings  `json:"reader"`
+	ReaderOverrides ReaderOverrides `json:"readerOverrides"`
 }
 
 // TODO(patrik): Move
@@ -63,9 +88,61 @@ func getPageOptions(q url.Values) database.FetchOptions {
 }
 
 func ConvertDBCollection(c pyrin.Context, collection database.Collection) Collection {
+	var release *CollectionRelease
+	if schedule, ok := core.GetReleaseSchedule(collection); ok {
+		release = &CollectionRelease{
+			Start:        collection.ReleaseStart.String,
+			DelayDays:    schedule.DelayDays,
+			IntervalDays: schedule.IntervalDays,
+			NumParts:     schedule.NumParts,
+			Status:       schedule.Status(),
+			CurrentPart:  schedule.CurrentPart(),
+		}
+
+		if date, part, ok := schedule.NextRelease(); ok {
+			d := date.Format(types.MediaDateLayout)
+			release.NextPart = &part
+			release.NextRelease = &d
+		}
+	}
+
+	var cover *types.Images
+	switch {
+	case collection.CoverHash.Valid:
+		url := ConvertURL(c, fmt.Sprintf("/files/collections/%s/cover/%s", collection.Id, collection.CoverFilename.String))
+		images := convertCollectionImages(c, collection.Id, collection.CoverHash.String, url)
+		cover = &images
+	case collection.CoverImageHash.Valid:
+		url := ConvertURL(c, fmt.Sprintf("/files/collections/%s/images/%s", collection.Id, collection.CoverImageFilename.String))
+		images := convertCollectionImages(c, collection.Id, collection.CoverImageHash.String, url)
+		cover = &images
+	}
+
 	return Collection{
-		Id:    collection.Id,
-		Title: collection.Title,
+		Id:           collection.Id,
+		Title:        collection.Title,
+		Series:       utils.SqlNullToStringPtr(collection.Series),
+		Volume:       utils.SqlNullToInt64Ptr(collection.Volume),
+		Cover:        cover,
+		CoverImageId: utils.SqlNullToStringPtr(collection.CoverImageId),
+		CustomCover:  collection.CoverHash.Valid,
+		Release:      release,
+	}
+}
+
+// NOTE(patrik): Thumbnails are named after the hash so the same naming is
+// used for both images and custom covers
+func convertCollectionImages(c pyrin.Context, collectionId, hash, url string) types.Images {
+	thumbnail := func(size string) string {
+		filename := core.ThumbnailFilename(hash, size)
+		return ConvertURL(c, fmt.Sprintf("/files/collections/%s/thumbnails/%s", collectionId, filename))
+	}
+
+	return types.Images{
+		Original: url,
+		Small:    thumbnail("small"),
+		Medium:   thumbnail("medium"),
+		Large:    thumbnail("large"),
 	}
 }
 
@@ -89,40 +166,158 @@ func (b CreateCollectionBody) Validate() error {
 
 type EditCollectionBody struct {
 	Title *string `json:"title,omitempty"`
+
+	// NOTE(patrik): Set Series to an empty string to remove the collection
+	// from the series, the volume is removed with it
+	Series *string `json:"series,omitempty"`
+	Volume *int    `json:"volume,omitempty"`
+
+	// NOTE(patrik): Set ReleaseStart to an empty string to remove the
+	// release schedule
+	ReleaseStart        *string `json:"releaseStart,omitempty"`
+	ReleaseDelayDays    *int    `json:"releaseDelayDays,omitempty"`
+	ReleaseIntervalDays *int    `json:"releaseIntervalDays,omitempty"`
+	ReleaseNumParts     *int    `json:"releaseNumParts,omitempty"`
+
+	// NOTE(patrik): Set to an empty string to use the reader default
+	ReaderDirection *string `json:"readerDirection,omitempty"`
+	ReaderLayout    *string `json:"readerLayout,omitempty"`
+	ReaderFit       *string `json:"readerFit,omitempty"`
 }
 
 func (b *EditCollectionBody) Transform() {
 	b.Title = anvil.StringPtr(b.Title)
+	b.Series = anvil.StringPtr(b.Series)
+	b.ReleaseStart = anvil.StringPtr(b.ReleaseStart)
+	b.ReaderDirection = anvil.StringPtr(b.ReaderDirection)
+	b.ReaderLayout = anvil.StringPtr(b.ReaderLayout)
+	b.ReaderFit = anvil.StringPtr(b.ReaderFit)
 }
 
 func (b EditCollectionBody) Validate() error {
 	return validate.ValidateStruct(&b,
 		validate.Field(&b.Title, validate.Required.When(b.Title != nil)),
+		validate.Field(&b.Volume, validate.Min(0)),
+		validate.Field(&b.ReleaseStart, validate.Date(types.MediaDateLayout)),
+		validate.Field(&b.ReleaseDelayDays, validate.Min(0)),
+		validate.Field(&b.ReleaseIntervalDays, validate.Required.When(b.ReleaseIntervalDays != nil), validate.Min(1)),
+		validate.Field(&b.ReleaseNumParts, validate.Min(0)),
+		validate.Field(&b.ReaderDirection, validate.By(types.ValidateReadingDirection)),
+		validate.Field(&b.ReaderLayout, validate.By(types.ValidateReaderLayout)),
+		validate.Field(&b.ReaderFit, validate.By(types.ValidateReaderFit)),
 	)
 }
 
 type CollectionImage struct {
+	Id           string `json:"id"`
 	CollectionId string `json:"collectionId"`
 	Hash         string `json:"hash"`
 	Filename     string `json:"filename"`
+	Position     int    `json:"position"`
 	Url          string `json:"url"`
+
+	Width     *int64  `json:"width,omitempty"`
+	Height    *int64  `json:"height,omitempty"`
+	Size      *int64  `json:"size,omitempty"`
+	MimeType  *string `json:"mimeType,omitempty"`
+	ColorMode *string `json:"colorMode,omitempty"`
+	Animated  *bool   `json:"animated,omitempty"`
+
+	// NOTE(patrik): Spread is true for landscape images, Split is set when
+	// the spread has been split into pages that follow it, readers should
+	// skip split spreads
+	Spread        bool    `json:"spread"`
+	Split         bool    `json:"split"`
+	SourceImageId *string `json:"sourceImageId,omitempty"`
+	SpreadSide    *string `json:"spreadSide,omitempty"`
+
+	Images types.Images `json:"images"`
 }
 
 func ConvertDBCollectionImage(c pyrin.Context, image database.Image) CollectionImage {
 	url := ConvertURL(c, fmt.Sprintf("/files/collections/%s/images/%s", image.CollectionId, image.Filename))
 
 	return CollectionImage{
-		CollectionId: image.CollectionId,
-		Hash:         image.Hash,
-		Filename:     image.Filename,
-		Url:          url,
+		Id:            image.Id,
+		CollectionId:  image.CollectionId,
+		Hash:          image.Hash,
+		Filename:      image.Filename,
+		Position:      image.Position,
+		Url:           url,
+		Width:         utils.SqlNullToInt64Ptr(image.Width),
+		Height:        utils.SqlNullToInt64Ptr(image.Height),
+		Size:          utils.SqlNullToInt64Ptr(image.Size),
+		MimeType:      utils.SqlNullToStringPtr(image.MimeType),
+		ColorMode:     utils.SqlNullToStringPtr(image.ColorMode),
+		Animated:      utils.SqlNullToBoolPtr(image.Animated),
+		Spread:        core.IsSpread(image),
+		Split:         image.Split.Valid,
+		SourceImageId: utils.SqlNullToStringPtr(image.SourceId),
+		SpreadSide:    utils.SqlNullToStringPtr(image.SpreadSide),
+		Images:        convertCollectionImages(c, image.CollectionId, image.Hash, url),
 	}
 }
 
+type EditCollectionImageBody struct {
+	Position *int `json:"position,omitempty"`
+}
+
+func (b EditCollectionImageBody) Validate() error {
+	return validate.ValidateStruct(&b,
+		validate.Field(&b.Position, validate.Min(0)),
+	)
+}
+
+type UploadToCollection struct {
+	JobIds []string `json:"jobIds"`
+}
+
 type GetCollectionImages struct {
 	Images []CollectionImage `json:"images"`
 }
 
+type SetCollectionCoverBody struct {
+	ImageId string `json:"imageId"`
+}
+
+func (b *SetCollectionCoverBody) Transform() {
+	b.ImageId = anvil.String(b.ImageId)
+}
+
+func (b SetCollectionCoverBody) Validate() error {
+	return validate.ValidateStruct(&b,
+		validate.Field(&b.ImageId, validate.Required),
+	)
+}
+
+type SplitCollectionImage struct {
+	Images []CollectionImage `json:"images"`
+}
+
+// NOTE(patrik): Images inside collections in the trash are treated as
+// missing so they can't be changed before the collection is restored
+func getCollectionImage(ctx context.Context, app core.App, collectionId, imageId string) (database.Image, error) {
+	_, err := app.DB().GetCollectionById(ctx, collectionId)
+	if err != nil {
+		if errors.Is(err, database.ErrItemNotFound) {
+			return database.Image{}, CollectionNotFound()
+		}
+
+		return database.Image{}, err
+	}
+
+	dbImage, err := app.DB().GetImageById(ctx, collectionId, imageId)
+	if err != nil {
+		if errors.Is(err, database.ErrItemNotFound) {
+			return database.Image{}, ImageNotFound()
+		}
+
+		return database.Image{}, err
+	}
+
+	return dbImage, nil
+}
+
 func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 	group.Register(
 		pyrin.ApiHandler{
@@ -134,9 +329,13 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 				q := c.Request().URL.Query()
 				opts := getPageOptions(q)
 
+				filter := database.CollectionFilter{
+					Query: q.Get("query"),
+				}
+
 				ctx := context.TODO()
 
-				collection, p, err := app.DB().GetPagedCollection(ctx, opts)
+				collection, p, err := app.DB().GetPagedCollection(ctx, filter, opts)
 				if err != nil {
 					return nil, err
 				}
@@ -171,8 +370,15 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 					return nil, err
 				}
 
+				reader, err := core.GetCollectionReaderSettings(c.Request().Context(), app, collection)
+				if err != nil {
+					return nil, err
+				}
+
 				return GetCollectionById{
-					Collection: ConvertDBCollection(c, collection),
+					Collection:      ConvertDBCollection(c, collection),
+					Reader:          ConvertReaderSettings(reader),
+					ReaderOverrides: ConvertReaderOverrides(collection),
 				}, nil
 			},
 		},
@@ -205,6 +411,10 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 					return nil, err
 				}
 
+				// NOTE(patrik): Use the same pages as the readers, the split
+				// spreads are replaced by their pages
+				images = core.ReadingPages(images)
+
 				res := GetCollectionImages{
 					Images: make([]CollectionImage, len(images)),
 				}
@@ -218,29 +428,416 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 		},
 
 		pyrin.ApiHandler{
-			Name:         "CreateCollection",
+			Name:         "EditCollectionImage",
+			Method:       http.MethodPatch,
+			Path:         "/collections/:id/images/:imageId",
+			ResponseType: nil,
+			BodyType:     EditCollectionImageBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+				imageId := c.Param("imageId")
+
+				body, err := pyrin.Body[EditCollectionImageBody](c)
+				if err != nil {
+					return nil, err
+				}
+
+				ctx := context.Background()
+
+				dbImage, err := getCollectionImage(ctx, app, id, imageId)
+				if err != nil {
+					return nil, err
+				}
+
+				tx, err := app.DB().Begin()
+				if err != nil {
+					return nil, err
+				}
+				defer tx.Rollback()
+
+				// NOTE(patrik): Refetch the image inside the transaction so
+				// the position is up to date
+				dbImage, err = tx.GetImageById(ctx, dbImage.CollectionId, dbImage.Id)
+				if err != nil {
+					return nil, err
+				}
+
+				changes := database.ImageChanges{}
+
+				if body.Position != nil {
+					next, err := tx.GetNextImagePosition(ctx, dbImage.CollectionId)
+					if err != nil {
+						return nil, err
+					}
+
+					position := utils.Clamp(*body.Position, 0, next-1)
+
+					// NOTE(patrik): The positions are unique so the image is
+					// parked after the last image while the others are moved
+					if position != dbImage.Position {
+						err = tx.UpdateImage(ctx, dbImage.CollectionId, dbImage.Id, database.ImageChanges{
+							Position: database.Change[int]{
+								Value:   next,
+								Changed: true,
+							},
+						})
+						if err != nil {
+							return nil, err
+						}
+					}
+
+					// NOTE(patrik): Move the images between the old and the
+					// new position one step to make room for the image
+					if position < dbImage.Position {
+						err = tx.ShiftImagePositions(ctx, dbImage.CollectionId, position, dbImage.Position-1, 1)
+						if err != nil {
+							return nil, err
+						}
+					} else if position > dbImage.Position {
+						err = tx.ShiftImagePositions(ctx, dbImage.CollectionId, dbImage.Position+1, position, -1)
+						if err != nil {
+							return nil, err
+						}
+					}
+
+					changes.Position = database.Change[int]{
+						Value:   position,
+						Changed: position != dbImage.Position,
+					}
+				}
+
+				err = tx.UpdateImage(ctx, dbImage.CollectionId, dbImage.Id, changes)
+				if err != nil {
+					return nil, err
+				}
+
+				err = tx.Commit()
+				if err != nil {
+					return nil, err
+				}
+
+				app.Broker().EmitEvent(core.CollectionEvent{
+					Type:         core.EventCollectionUpdated,
+					CollectionId: dbImage.CollectionId,
+				})
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "DeleteCollectionImage",
+			Method:       http.MethodDelete,
+			Path:         "/collections/:id/images/:imageId",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+				imageId := c.Param("imageId")
+
+				ctx := context.Background()
+
+				dbImage, err := getCollectionImage(ctx, app, id, imageId)
+				if err != nil {
+					return nil, err
+				}
+
+				err = core.RemoveCollectionImage(ctx, app, dbImage.CollectionId, dbImage.Id)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.FormApiHandler{
+			Name:   "ReplaceCollectionImage",
+			Method: http.MethodPut,
+			Path:   "/collections/:id/images/:imageId",
+			Spec: pyrin.FormSpec{
+				Files: map[string]pyrin.FormFileSpec{
+					"file": {
+						NumExpected: 1,
+					},
+				},
+			},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+				imageId := c.Param("imageId")
+
+				ctx := c.Request().Context()
+
+				dbImage, err := getCollectionImage(ctx, app, id, imageId)
+				if err != nil {
+					return nil, err
+				}
+
+				files, err := pyrin.FormFiles(c, "file")
+				if err != nil {
+					return nil, err
+				}
+
+				f := files[0]
+
+				file, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer file.Close()
+
+				data, err := io.ReadAll(file)
+				if err != nil {
+					return nil, err
+				}
+
+				ext := strings.ToLower(path.Ext(f.Filename))
+
+				err = core.ReplaceCollectionImage(ctx, app, dbImage.CollectionId, dbImage.Id, data, ext)
+				if err != nil {
+					if errors.Is(err, database.ErrItemAlreadyExists) {
+						return nil, ImageAlreadyExists()
+					}
+
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.FormApiHandler{
+			Name:         "UploadCollectionCover",
 			Method:       http.MethodPost,
-			Path:         "/collections",
-			ResponseType: CreateCollection{},
-			BodyType:     CreateCollectionBody{},
+			Path:         "/collections/:id/cover",
+			ResponseType: nil,
+			Spec: pyrin.FormSpec{
+				Files: map[string]pyrin.FormFileSpec{
+					"file": {
+						NumExpected: 1,
+					},
+				},
+			},
 			HandlerFunc: func(c pyrin.Context) (any, error) {
-				body, err := pyrin.Body[CreateCollectionBody](c)
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				dbCollection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				files, err := pyrin.FormFiles(c, "file")
+				if err != nil {
+					return nil, err
+				}
+
+				f := files[0]
+
+				file, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer file.Close()
+
+				data, err := io.ReadAll(file)
+				if err != nil {
+					return nil, err
+				}
+
+				err = core.SetCustomCover(ctx, app, dbCollection, data, f.Filename)
+				if err != nil {
+					if errors.Is(err, core.ErrInvalidCoverImage) {
+						return nil, InvalidImage()
+					}
+
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "SetCollectionCover",
+			Method:       http.MethodPatch,
+			Path:         "/collections/:id/cover",
+			ResponseType: nil,
+			BodyType:     SetCollectionCoverBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				body, err := pyrin.Body[SetCollectionCoverBody](c)
 				if err != nil {
 					return nil, err
 				}
 
 				ctx := context.Background()
 
-				id := utils.CreateCollectionId()
+				dbCollection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				_, err = app.DB().GetImageById(ctx, dbCollection.Id, body.ImageId)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, ImageNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = core.SetCoverImage(ctx, app, dbCollection, body.ImageId)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "ResetCollectionCover",
+			Method:       http.MethodDelete,
+			Path:         "/collections/:id/cover",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
 
-				collectionDir := app.WorkDir().CollectionDirById(id)
-				err = collectionDir.Create()
+				ctx := context.Background()
+
+				dbCollection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = core.ResetCover(ctx, app, dbCollection)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "SplitCollectionImage",
+			Method:       http.MethodPost,
+			Path:         "/collections/:id/images/:imageId/split",
+			ResponseType: SplitCollectionImage{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+				imageId := c.Param("imageId")
+
+				ctx := context.Background()
+
+				dbImage, err := getCollectionImage(ctx, app, id, imageId)
+				if err != nil {
+					return nil, err
+				}
+
+				ids, err := core.SplitSpread(ctx, app, dbImage)
+				if err != nil {
+					switch {
+					case errors.Is(err, core.ErrImageNotSpread):
+						return nil, ImageNotSpread()
+					case errors.Is(err, core.ErrImageAlreadySplit):
+						return nil, ImageAlreadySplit()
+					case errors.Is(err, core.ErrSpreadPagesExists),
+						errors.Is(err, database.ErrItemAlreadyExists):
+						return nil, ImageAlreadyExists()
+					}
+
+					return nil, err
+				}
+
+				res := SplitCollectionImage{
+					Images: make([]CollectionImage, 0, len(ids)),
+				}
+
+				for _, pageId := range ids {
+					page, err := app.DB().GetImageById(ctx, id, pageId)
+					if err != nil {
+						return nil, err
+					}
+
+					res.Images = append(res.Images, ConvertDBCollectionImage(c, page))
+				}
+
+				_, err = app.Jobs().Enqueue(ctx, core.JobTypeGenerateThumbnails, core.GenerateThumbnailsPayload{
+					CollectionId: id,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				app.Broker().EmitEvent(core.CollectionEvent{
+					Type:         core.EventCollectionUpdated,
+					CollectionId: id,
+				})
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "SplitCollectionSpreads",
+			Method:       http.MethodPost,
+			Path:         "/collections/:id/spreads/split",
+			ResponseType: CreateJob{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				_, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				jobId, err := app.Jobs().Enqueue(ctx, core.JobTypeSplitSpreads, core.SplitSpreadsPayload{
+					CollectionId: id,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "CreateCollection",
+			Method:       http.MethodPost,
+			Path:         "/collections",
+			ResponseType: CreateCollection{},
+			BodyType:     CreateCollectionBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				body, err := pyrin.Body[CreateCollectionBody](c)
 				if err != nil {
 					return nil, err
 				}
 
-				_, err = app.DB().CreateCollection(ctx, database.CreateCollectionParams{
-					Id:    id,
+				id, err := core.CreateCollection(context.Background(), app, database.CreateCollectionParams{
 					Title: body.Title,
 				})
 				if err != nil {
@@ -287,11 +884,113 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 					}
 				}
 
+				if body.Series != nil {
+					changes.Series = database.Change[sql.NullString]{
+						Value: sql.NullString{
+							String: *body.Series,
+							Valid:  *body.Series != "",
+						},
+						Changed: *body.Series != dbCollection.Series.String,
+					}
+
+					if *body.Series == "" {
+						changes.Volume = database.Change[sql.NullInt64]{
+							Changed: dbCollection.Volume.Valid,
+						}
+					}
+				}
+
+				if body.Volume != nil && (body.Series == nil || *body.Series != "") {
+					changes.Volume = database.Change[sql.NullInt64]{
+						Value: sql.NullInt64{
+							Int64: int64(*body.Volume),
+							Valid: true,
+						},
+						Changed: !dbCollection.Volume.Valid || dbCollection.Volume.Int64 != int64(*body.Volume),
+					}
+				}
+
+				if body.ReaderDirection != nil {
+					changes.ReaderDirection = core.ReaderOverrideChange(*body.ReaderDirection, dbCollection.ReaderDirection)
+				}
+
+				if body.ReaderLayout != nil {
+					changes.ReaderLayout = core.ReaderOverrideChange(*body.ReaderLayout, dbCollection.ReaderLayout)
+				}
+
+				if body.ReaderFit != nil {
+					changes.ReaderFit = core.ReaderOverrideChange(*body.ReaderFit, dbCollection.ReaderFit)
+				}
+
+				releaseChanged := false
+
+				if body.ReleaseStart != nil {
+					changes.ReleaseStart = database.Change[sql.NullString]{
+						Value: sql.NullString{
+							String: *body.ReleaseStart,
+							Valid:  *body.ReleaseStart != "",
+						},
+						Changed: true,
+					}
+
+					dbCollection.ReleaseStart = changes.ReleaseStart.Value
+					releaseChanged = true
+				}
+
+				if body.ReleaseDelayDays != nil {
+					changes.ReleaseDelayDays = database.Change[int]{
+						Value:   *body.ReleaseDelayDays,
+						Changed: *body.ReleaseDelayDays != dbCollection.ReleaseDelayDays,
+					}
+
+					dbCollection.ReleaseDelayDays = *body.ReleaseDelayDays
+					releaseChanged = true
+				}
+
+				if body.ReleaseIntervalDays != nil {
+					changes.ReleaseIntervalDays = database.Change[int]{
+						Value:   *body.ReleaseIntervalDays,
+						Changed: *body.ReleaseIntervalDays != dbCollection.ReleaseIntervalDays,
+					}
+
+					dbCollection.ReleaseIntervalDays = *body.ReleaseIntervalDays
+					releaseChanged = true
+				}
+
+				if body.ReleaseNumParts != nil {
+					changes.ReleaseNumParts = database.Change[int]{
+						Value:   *body.ReleaseNumParts,
+						Changed: *body.ReleaseNumParts != dbCollection.ReleaseNumParts,
+					}
+
+					dbCollection.ReleaseNumParts = *body.ReleaseNumParts
+					releaseChanged = true
+				}
+
+				// NOTE(patrik): Don't send notifications for the parts that
+				// was released before the schedule was changed
+				if releaseChanged {
+					notified := 0
+					if schedule, ok := core.GetReleaseSchedule(dbCollection); ok {
+						notified = schedule.CurrentPart()
+					}
+
+					changes.ReleaseNotifiedPart = database.Change[int]{
+						Value:   notified,
+						Changed: true,
+					}
+				}
+
 				err = app.DB().UpdateCollection(ctx, dbCollection.Id, changes)
 				if err != nil {
 					return nil, err
 				}
 
+				app.Broker().EmitEvent(core.CollectionEvent{
+					Type:         core.EventCollectionUpdated,
+					CollectionId: dbCollection.Id,
+				})
+
 				return nil, nil
 			},
 		},
@@ -315,25 +1014,33 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 					return nil, err
 				}
 
-				err = app.DB().RemoveCollection(ctx, dbCollection.Id)
+				err = app.DB().UpdateCollection(ctx, dbCollection.Id, database.CollectionChanges{
+					Deleted: database.Change[sql.NullInt64]{
+						Value: sql.NullInt64{
+							Int64: time.Now().UnixMilli(),
+							Valid: true,
+						},
+						Changed: true,
+					},
+				})
 				if err != nil {
 					return nil, err
 				}
 
-				dir := app.WorkDir().CollectionDirById(dbCollection.Id)
-				err = os.RemoveAll(dir.String())
-				if err != nil {
-					return nil, err
-				}
+				app.Broker().EmitEvent(core.CollectionEvent{
+					Type:         core.EventCollectionDeleted,
+					CollectionId: dbCollection.Id,
+				})
 
 				return nil, nil
 			},
 		},
 
 		pyrin.FormApiHandler{
-			Name:   "UploadToCollection",
-			Method: http.MethodPost,
-			Path:   "/collections/:id/upload",
+			Name:         "UploadToCollection",
+			Method:       http.MethodPost,
+			Path:         "/collections/:id/upload",
+			ResponseType: UploadToCollection{},
 			Spec: pyrin.FormSpec{
 				Files: map[string]pyrin.FormFileSpec{
 					"file": {
@@ -355,93 +1062,119 @@ func InstallCollectionHandlers(app core.App, group pyrin.Group) {
 					return nil, err
 				}
 
-				collectionDir := app.WorkDir().CollectionDirById(dbCollection.Id)
-				err = collectionDir.Create()
+				files, err := pyrin.FormFiles(c, "file")
 				if err != nil {
 					return nil, err
 				}
 
-				files, err := pyrin.FormFiles(c, "file")
-				if err != nil {
-					return nil, err
+				// NOTE(patrik): Browsers and clients often sends archives as
+				// application/octet-stream so the extension is checked as
+				// well, the whole upload is rejected before anything is
+				// stored if one of the files isn't an archive
+				for _, f := range files {
+					if core.IsArchiveExt(path.Ext(f.Filename)) {
+						continue
+					}
+
+					mediaType, _, _ := mime.ParseMediaType(f.Header.Get("Content-Type"))
+					switch mediaType {
+					case "application/zip", "application/x-cbz", "application/vnd.comicbook+zip":
+						continue
+					}
+
+					return nil, InvalidArchive(f.Filename)
 				}
 
+				res := UploadToCollection{
+					JobIds: []string{},
+				}
+
+				// NOTE(patrik): Store the uploaded archives and let the job
+				// runner import them so the request doesn't block
 				for _, f := range files {
-					fmt.Printf("f.Filename: %v\n", f.Filename)
-					contentType := f.Header.Get("Content-Type")
-					mediaType, _, err := mime.ParseMediaType(contentType)
+					out := path.Join(app.WorkDir().UploadsDir(), utils.CreateId()+".zip")
+					err = saveFormFile(f, out)
 					if err != nil {
 						return nil, err
 					}
 
-					readZip := func() error {
-						file, err := f.Open()
-						if err != nil {
-							return err
-						}
+					jobId, err := app.Jobs().Enqueue(ctx, core.JobTypeImportArchive, core.ImportArchivePayload{
+						CollectionId: dbCollection.Id,
+						File:         out,
+						RemoveFile:   true,
+					})
+					if err != nil {
+						return nil, err
+					}
 
-						r, err := zip.NewReader(file, f.Size)
-						if err != nil {
-							return err
-						}
+					res.JobIds = append(res.JobIds, jobId)
+				}
 
-						sort.SliceStable(r.File, func(i, j int) bool {
-							return natural.Less(r.File[i].Name, r.File[j].Name)
-						})
+				return res, nil
+			},
+		},
 
-						importFile := func(db *database.DB, zf *zip.File) error {
-							r, err := zf.Open()
-							if err != nil {
-								return err
-							}
-
-							defer r.Close()
-
-							data, err := io.ReadAll(r)
-							if err != nil {
-								return err
-							}
-
-							ext := path.Ext(zf.Name)
-							out, hash, err := utils.WriteHashedFile(data, collectionDir.Images(), ext)
-							if err != nil {
-								return err
-							}
-
-							fmt.Printf("out: %v\n", out)
-
-							err = db.CreateImage(ctx, database.CreateImageParams{
-								CollectionId: dbCollection.Id,
-								Hash:         hash,
-								Filename:     path.Base(out),
-							})
-							if err != nil {
-								return err
-							}
-
-							return nil
-						}
+		pyrin.ApiHandler{
+			Name:         "GenerateCollectionThumbnails",
+			Method:       http.MethodPost,
+			Path:         "/collections/:id/thumbnails",
+			ResponseType: CreateJob{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
 
-						for _, zf := range r.File {
-							err := importFile(&app.DB().DB, zf)
-							if err != nil {
-								return err
-							}
-						}
+				ctx := context.Background()
 
-						return nil
+				dbCollection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
 					}
 
-					switch mediaType {
-					case "application/zip":
-						err := readZip()
-						if err != nil {
-							return nil, err
-						}
+					return nil, err
+				}
+
+				jobId, err := app.Jobs().Enqueue(ctx, core.JobTypeGenerateThumbnails, core.GenerateThumbnailsPayload{
+					CollectionId: dbCollection.Id,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "ExportCollection",
+			Method:       http.MethodPost,
+			Path:         "/collections/:id/export",
+			ResponseType: CreateJob{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				dbCollection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
 					}
+
+					return nil, err
 				}
 
-				return nil, nil
+				jobId, err := app.Jobs().Enqueue(ctx, core.JobTypeExportCollection, core.ExportCollectionPayload{
+					CollectionId: dbCollection.Id,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
 			},
 		},
 	)
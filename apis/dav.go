@@ -0,0 +1,78 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"golang.org/x/net/webdav"
+)
+
+// NOTE(patrik): Only the methods needed for reading are routed, the rest
+// gets a 405 from the router. OPTIONS is routed so the handler can answer
+// with the DAV header, but the CORS middleware pyrin installs on the server
+// still answers every OPTIONS request before the routes, until pyrin lets
+// the server skip it for a prefix clients probing for the header sees a
+// plain 204
+var davMethods = []string{
+	http.MethodOptions,
+	http.MethodGet,
+	http.MethodHead,
+	"PROPFIND",
+}
+
+func InstallDavHandlers(app core.App, group pyrin.Group) {
+	handler := &webdav.Handler{
+		Prefix:     "/dav",
+		FileSystem: core.NewDavFileSystem(app),
+		// NOTE(patrik): Required by the handler but never used because
+		// LOCK isn't routed
+		LockSystem: webdav.NewMemLS(),
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				app.Logger().Debug("WebDAV request failed", "method", r.Method, "path", r.URL.Path, "err", err)
+			}
+		},
+	}
+
+	// NOTE(patrik): The routes are always registered and the config is
+	// checked on each request, disabled mounts responds like a missing
+	// route
+	enabled := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !app.Config().WebDav {
+				return pyrin.RouteNotFound()
+			}
+
+			return next(c)
+		}
+	}
+
+	middlewares := []echo.MiddlewareFunc{enabled, basicAuth(app)}
+
+	serve := func(c pyrin.Context) error {
+		r := c.Request()
+		handler.ServeHTTP(c.Response(), r.WithContext(core.WithDavCache(r.Context())))
+		return nil
+	}
+
+	for _, method := range davMethods {
+		group.Register(
+			pyrin.NormalHandler{
+				Name:        "Dav" + method,
+				Method:      method,
+				Path:        "",
+				Middlewares: middlewares,
+				HandlerFunc: serve,
+			},
+			pyrin.NormalHandler{
+				Name:        "DavPath" + method,
+				Method:      method,
+				Path:        "/*",
+				Middlewares: middlewares,
+				HandlerFunc: serve,
+			},
+		)
+	}
+}
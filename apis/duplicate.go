@@ -0,0 +1,218 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+)
+
+const defaultMinOverlap = 0.8
+
+type DuplicateGroup struct {
+	Images []CollectionImage `json:"images"`
+}
+
+type GetCollectionDuplicates struct {
+	Threshold int              `json:"threshold"`
+	Groups    []DuplicateGroup `json:"groups"`
+}
+
+type DuplicateCollection struct {
+	Collection Collection `json:"collection"`
+
+	// NOTE(patrik): Number of pages that has a near duplicate inside the
+	// other collection
+	Matched int `json:"matched"`
+	Total   int `json:"total"`
+}
+
+type DuplicateCollectionPair struct {
+	A DuplicateCollection `json:"a"`
+	B DuplicateCollection `json:"b"`
+
+	Overlap float64 `json:"overlap"`
+}
+
+type GetDuplicateCollections struct {
+	Threshold  int                       `json:"threshold"`
+	MinOverlap float64                   `json:"minOverlap"`
+	Pairs      []DuplicateCollectionPair `json:"pairs"`
+}
+
+func getDuplicateThreshold(q url.Values) (int, error) {
+	s := q.Get("threshold")
+	if s == "" {
+		return core.DefaultDuplicateThreshold, nil
+	}
+
+	threshold, err := strconv.Atoi(s)
+	if err != nil || threshold < 0 || threshold > 64 {
+		return 0, errors.New("threshold needs to be between 0 and 64")
+	}
+
+	return threshold, nil
+}
+
+func InstallDuplicateHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetCollectionDuplicates",
+			Method:       http.MethodGet,
+			Path:         "/collections/:id/duplicates",
+			ResponseType: GetCollectionDuplicates{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+				q := c.Request().URL.Query()
+
+				threshold, err := getDuplicateThreshold(q)
+				if err != nil {
+					return nil, InvalidFilter(err)
+				}
+
+				// NOTE(patrik): "collection" only looks for duplicates
+				// inside the collection, "library" also looks inside the
+				// other collections
+				scope := q.Get("scope")
+				if scope == "" {
+					scope = "collection"
+				}
+
+				if scope != "collection" && scope != "library" {
+					return nil, InvalidFilter(errors.New("scope needs to be collection or library"))
+				}
+
+				ctx := c.Request().Context()
+
+				dbCollection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				var images []database.Image
+				var filter func(img database.Image) bool
+
+				if scope == "library" {
+					images, err = app.DB().GetImagesWithPHash(ctx)
+					if err != nil {
+						return nil, err
+					}
+
+					filter = func(img database.Image) bool {
+						return img.CollectionId == dbCollection.Id
+					}
+				} else {
+					images, err = app.DB().GetAllImagesByCollectionId(ctx, dbCollection.Id)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				groups := core.FindNearDuplicateImages(images, threshold, filter)
+
+				res := GetCollectionDuplicates{
+					Threshold: threshold,
+					Groups:    make([]DuplicateGroup, len(groups)),
+				}
+
+				for i, group := range groups {
+					res.Groups[i].Images = make([]CollectionImage, len(group))
+					for j, img := range group {
+						res.Groups[i].Images[j] = ConvertDBCollectionImage(c, img)
+					}
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "GetDuplicateCollections",
+			Method:       http.MethodGet,
+			Path:         "/duplicates/collections",
+			ResponseType: GetDuplicateCollections{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				q := c.Request().URL.Query()
+
+				threshold, err := getDuplicateThreshold(q)
+				if err != nil {
+					return nil, InvalidFilter(err)
+				}
+
+				minOverlap := defaultMinOverlap
+				if s := q.Get("minOverlap"); s != "" {
+					minOverlap, err = strconv.ParseFloat(s, 64)
+					if err != nil || minOverlap <= 0 || minOverlap > 1 {
+						return nil, InvalidFilter(errors.New("minOverlap needs to be between 0 and 1"))
+					}
+				}
+
+				ctx := c.Request().Context()
+
+				images, err := app.DB().GetImagesWithPHash(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				overlaps := core.FindDuplicateCollections(images, threshold, minOverlap)
+
+				collections := make(map[string]database.Collection)
+				getCollection := func(id string) (database.Collection, error) {
+					if collection, exists := collections[id]; exists {
+						return collection, nil
+					}
+
+					collection, err := app.DB().GetCollectionById(ctx, id)
+					if err != nil {
+						return database.Collection{}, err
+					}
+
+					collections[id] = collection
+					return collection, nil
+				}
+
+				res := GetDuplicateCollections{
+					Threshold:  threshold,
+					MinOverlap: minOverlap,
+					Pairs:      make([]DuplicateCollectionPair, len(overlaps)),
+				}
+
+				for i, overlap := range overlaps {
+					a, err := getCollection(overlap.CollectionA)
+					if err != nil {
+						return nil, err
+					}
+
+					b, err := getCollection(overlap.CollectionB)
+					if err != nil {
+						return nil, err
+					}
+
+					res.Pairs[i] = DuplicateCollectionPair{
+						A: DuplicateCollection{
+							Collection: ConvertDBCollection(c, a),
+							Matched:    overlap.MatchedA,
+							Total:      overlap.TotalA,
+						},
+						B: DuplicateCollection{
+							Collection: ConvertDBCollection(c, b),
+							Matched:    overlap.MatchedB,
+							Total:      overlap.TotalB,
+						},
+						Overlap: overlap.Overlap(),
+					}
+				}
+
+				return res, nil
+			},
+		},
+	)
+}
@@ -28,8 +28,18 @@ const (
 	ErrTypeShowNotFound             pyrin.ErrorType = "SHOW_NOT_FOUND"
 	ErrTypeShowSeasonNotFound       pyrin.ErrorType = "SHOW_SEASON_NOT_FOUND"
 	ErrTypeShowSeasonItemNotFound   pyrin.ErrorType = "SHOW_SEASON_ITEM_NOT_FOUND"
+	ErrTypeJobNotFound              pyrin.ErrorType = "JOB_NOT_FOUND"
+	ErrTypeTaskNotFound             pyrin.ErrorType = "TASK_NOT_FOUND"
 
-	ErrTypePartAlreadyExists pyrin.ErrorType = "PART_ALREADY_EXISTS"
+	ErrTypeInvalidJobState pyrin.ErrorType = "INVALID_JOB_STATE"
+	ErrTypeTaskRunning     pyrin.ErrorType = "TASK_RUNNING"
+	ErrTypeImageNotSpread  pyrin.ErrorType = "IMAGE_NOT_SPREAD"
+	ErrTypeImageSplit      pyrin.ErrorType = "IMAGE_SPLIT"
+	ErrTypeInvalidImage    pyrin.ErrorType = "INVALID_IMAGE"
+	ErrTypeInvalidArchive  pyrin.ErrorType = "INVALID_ARCHIVE"
+
+	ErrTypePartAlreadyExists  pyrin.ErrorType = "PART_ALREADY_EXISTS"
+	ErrTypeImageAlreadyExists pyrin.ErrorType = "IMAGE_ALREADY_EXISTS"
 )
 
 func InvalidAuth(message string) *pyrin.Error {
@@ -152,6 +162,38 @@ func ShowSeasonItemNotFound() *pyrin.Error {
 	}
 }
 
+func JobNotFound() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusNotFound,
+		Type:    ErrTypeJobNotFound,
+		Message: "Job not found",
+	}
+}
+
+func InvalidJobState(message string) *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeInvalidJobState,
+		Message: message,
+	}
+}
+
+func TaskNotFound() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusNotFound,
+		Type:    ErrTypeTaskNotFound,
+		Message: "Task not found",
+	}
+}
+
+func TaskRunning() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeTaskRunning,
+		Message: "Task is already running",
+	}
+}
+
 func PartAlreadyExists() *pyrin.Error {
 	return &pyrin.Error{
 		Code:    http.StatusBadRequest,
@@ -160,6 +202,46 @@ func PartAlreadyExists() *pyrin.Error {
 	}
 }
 
+func ImageNotSpread() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeImageNotSpread,
+		Message: "Image is not a spread",
+	}
+}
+
+func ImageAlreadySplit() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeImageSplit,
+		Message: "Image is already split",
+	}
+}
+
+func InvalidImage() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeInvalidImage,
+		Message: "Invalid image",
+	}
+}
+
+func InvalidArchive(filename string) *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeInvalidArchive,
+		Message: "Unsupported archive (expected a zip or cbz): " + filename,
+	}
+}
+
+func ImageAlreadyExists() *pyrin.Error {
+	return &pyrin.Error{
+		Code:    http.StatusBadRequest,
+		Type:    ErrTypeImageAlreadyExists,
+		Message: "Image already exists",
+	}
+}
+
 func UserAlreadyExists() *pyrin.Error {
 	return &pyrin.Error{
 		Code:    http.StatusBadRequest,
@@ -1,18 +1,48 @@
 package apis
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 	"github.com/nanoteck137/pyrin"
 	"github.com/nanoteck137/storebook"
 	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/storage"
 	"github.com/nanoteck137/storebook/utils"
 )
 
 // TODO(patrik): Remove
 var logger = storebook.DefaultLogger()
 
+// basicAuth checks the storebook password for the clients that can only
+// do basic auth, like the OPDS readers and WebDAV clients
+func basicAuth(app core.App) echo.MiddlewareFunc {
+	return middleware.BasicAuthWithConfig(middleware.BasicAuthConfig{
+		Realm: "storebook",
+		// NOTE(patrik): There is only a single user so any username is
+		// accepted as long as the password is correct
+		Validator: func(username, password string, c echo.Context) (bool, error) {
+			expected := app.Config().Password
+			if expected == "" {
+				return false, nil
+			}
+
+			return subtle.ConstantTimeCompare([]byte(password), []byte(expected)) == 1, nil
+		},
+	})
+}
+
 func LoggedIn(app core.App, c pyrin.Context) error {
 	passwordHeader := c.Request().Header.Get("X-Password")
 	if passwordHeader != "" {
@@ -27,6 +57,10 @@ func LoggedIn(app core.App, c pyrin.Context) error {
 		return InvalidAuth("invalid authorization header")
 	}
 
+	return checkToken(app, tokenString)
+}
+
+func checkToken(app core.App, tokenString string) error {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -64,3 +98,65 @@ func ConvertURL(c pyrin.Context, path string) string {
 
 	return fmt.Sprintf("%s://%s%s", scheme, host, path)
 }
+
+func saveFormFile(f *multipart.FileHeader, out string) error {
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func serveImage(c pyrin.Context, app core.App, image database.Image) error {
+	return serveBlob(c, app, core.ImageKey(image), image.Filename)
+}
+
+// NOTE(patrik): The blobs are stored without extensions so the filename
+// is used to get the content type
+func serveBlob(c pyrin.Context, app core.App, key, filename string) error {
+	ctx := c.Request().Context()
+	contentType, _ := utils.ImageExtToContentType(strings.ToLower(path.Ext(filename)))
+
+	if presigner, ok := app.Storage().(storage.Presigner); ok {
+		url, err := presigner.PresignGet(ctx, key, contentType)
+		if err == nil {
+			http.Redirect(c.Response(), c.Request(), url, http.StatusFound)
+			return nil
+		}
+
+		if !errors.Is(err, storage.ErrPresignDisabled) {
+			return err
+		}
+	}
+
+	f, obj, err := storage.Open(ctx, app.Storage(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return pyrin.NoContentNotFound()
+		}
+
+		return err
+	}
+	defer f.Close()
+
+	if contentType != "" {
+		c.Response().Header().Set("Content-Type", contentType)
+	}
+
+	http.ServeContent(c.Response(), c.Request(), filename, obj.ModTime, f)
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+type JobLog struct {
+	Message string `json:"message"`
+	Created int64  `json:"created"`
+}
+
+type Job struct {
+	Id string `json:"id"`
+
+	Type   string          `json:"type"`
+	Status types.JobStatus `json:"status"`
+
+	// NOTE(patrik): Payload and Result is stored as JSON strings
+	Payload string  `json:"payload"`
+	Result  *string `json:"result,omitempty"`
+	Error   *string `json:"error,omitempty"`
+
+	ProgressCurrent int `json:"progressCurrent"`
+	ProgressTotal   int `json:"progressTotal"`
+
+	Attempts int `json:"attempts"`
+
+	Started  *int64 `json:"started,omitempty"`
+	Finished *int64 `json:"finished,omitempty"`
+
+	Created int64 `json:"created"`
+	Updated int64 `json:"updated"`
+}
+
+type GetJobs struct {
+	Page types.Page `json:"page"`
+	Jobs []Job      `json:"jobs"`
+}
+
+type GetJobById struct {
+	Job
+
+	Logs []JobLog `json:"logs"`
+}
+
+type CreateJob struct {
+	JobId string `json:"jobId"`
+}
+
+func ConvertDBJob(job database.Job) Job {
+	return Job{
+		Id:              job.Id,
+		Type:            job.Type,
+		Status:          job.Status,
+		Payload:         job.Payload,
+		Result:          utils.SqlNullToStringPtr(job.Result),
+		Error:           utils.SqlNullToStringPtr(job.Error),
+		ProgressCurrent: job.ProgressCurrent,
+		ProgressTotal:   job.ProgressTotal,
+		Attempts:        job.Attempts,
+		Started:         utils.SqlNullToInt64Ptr(job.Started),
+		Finished:        utils.SqlNullToInt64Ptr(job.Finished),
+		Created:         job.Created,
+		Updated:         job.Updated,
+	}
+}
+
+func InstallJobHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetJobs",
+			Method:       http.MethodGet,
+			Path:         "/jobs",
+			ResponseType: GetJobs{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				q := c.Request().URL.Query()
+				opts := getPageOptions(q)
+
+				status := types.JobStatus(q.Get("status"))
+				if status != "" && !types.IsValidJobStatus(status) {
+					return nil, InvalidFilter(errors.New("invalid job status"))
+				}
+
+				filter := database.JobFilter{
+					Status: status,
+					Type:   q.Get("type"),
+				}
+
+				ctx := context.TODO()
+
+				jobs, p, err := app.DB().GetPagedJobs(ctx, filter, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				res := GetJobs{
+					Page: p,
+					Jobs: make([]Job, len(jobs)),
+				}
+
+				for i, job := range jobs {
+					res.Jobs[i] = ConvertDBJob(job)
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "GetJobById",
+			Method:       http.MethodGet,
+			Path:         "/jobs/:id",
+			ResponseType: GetJobById{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := c.Request().Context()
+
+				job, err := app.DB().GetJobById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, JobNotFound()
+					}
+
+					return nil, err
+				}
+
+				logs, err := app.DB().GetJobLogs(ctx, job.Id)
+				if err != nil {
+					return nil, err
+				}
+
+				res := GetJobById{
+					Job:  ConvertDBJob(job),
+					Logs: make([]JobLog, len(logs)),
+				}
+
+				for i, log := range logs {
+					res.Logs[i] = JobLog{
+						Message: log.Message,
+						Created: log.Created,
+					}
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "CancelJob",
+			Method:       http.MethodPost,
+			Path:         "/jobs/:id/cancel",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				err := app.Jobs().Cancel(context.Background(), id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, JobNotFound()
+					}
+
+					if errors.Is(err, core.ErrJobNotCancellable) {
+						return nil, InvalidJobState("Job is not queued or running")
+					}
+
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "RetryJob",
+			Method:       http.MethodPost,
+			Path:         "/jobs/:id/retry",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				err := app.Jobs().Retry(context.Background(), id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, JobNotFound()
+					}
+
+					if errors.Is(err, core.ErrJobNotRetryable) {
+						return nil, InvalidJobState("Only failed or cancelled jobs can be retried")
+					}
+
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+	)
+}
@@ -0,0 +1,207 @@
+package apis
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+type Notification struct {
+	Id string `json:"id"`
+
+	Type types.NotificationType `json:"type"`
+
+	Title   string `json:"title"`
+	Message string `json:"message"`
+
+	CollectionId *string `json:"collectionId,omitempty"`
+	JobId        *string `json:"jobId,omitempty"`
+
+	IsRead bool   `json:"isRead"`
+	Read   *int64 `json:"read,omitempty"`
+
+	Created int64 `json:"created"`
+}
+
+type GetNotifications struct {
+	Page          types.Page     `json:"page"`
+	UnreadCount   int            `json:"unreadCount"`
+	Notifications []Notification `json:"notifications"`
+}
+
+func ConvertDBNotification(notification database.Notification) Notification {
+	return Notification{
+		Id:           notification.Id,
+		Type:         notification.Type,
+		Title:        notification.Title,
+		Message:      notification.Message,
+		CollectionId: utils.SqlNullToStringPtr(notification.CollectionId),
+		JobId:        utils.SqlNullToStringPtr(notification.JobId),
+		IsRead:       notification.Read.Valid,
+		Read:         utils.SqlNullToInt64Ptr(notification.Read),
+		Created:      notification.Created,
+	}
+}
+
+func InstallNotificationHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetNotifications",
+			Method:       http.MethodGet,
+			Path:         "/notifications",
+			ResponseType: GetNotifications{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				q := c.Request().URL.Query()
+				opts := getPageOptions(q)
+
+				filter := database.NotificationFilter{
+					OnlyUnread: q.Get("unread") == "true",
+				}
+
+				ctx := context.TODO()
+
+				notifications, p, err := app.DB().GetPagedNotifications(ctx, filter, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				unreadCount, err := app.DB().GetUnreadNotificationCount(ctx)
+				if err != nil {
+					return nil, err
+				}
+
+				res := GetNotifications{
+					Page:          p,
+					UnreadCount:   unreadCount,
+					Notifications: make([]Notification, len(notifications)),
+				}
+
+				for i, notification := range notifications {
+					res.Notifications[i] = ConvertDBNotification(notification)
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "MarkNotificationRead",
+			Method:       http.MethodPost,
+			Path:         "/notifications/:id/read",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				notification, err := app.DB().GetNotificationById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, NotificationNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = app.DB().UpdateNotification(ctx, notification.Id, database.NotificationChanges{
+					Read: database.Change[sql.NullInt64]{
+						Value: sql.NullInt64{
+							Int64: time.Now().UnixMilli(),
+							Valid: true,
+						},
+						Changed: !notification.Read.Valid,
+					},
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "MarkNotificationUnread",
+			Method:       http.MethodPost,
+			Path:         "/notifications/:id/unread",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				notification, err := app.DB().GetNotificationById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, NotificationNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = app.DB().UpdateNotification(ctx, notification.Id, database.NotificationChanges{
+					Read: database.Change[sql.NullInt64]{
+						Value:   sql.NullInt64{},
+						Changed: notification.Read.Valid,
+					},
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "MarkAllNotificationsRead",
+			Method:       http.MethodPost,
+			Path:         "/notifications/read",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := app.DB().MarkAllNotificationsRead(context.Background())
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "DismissNotification",
+			Method:       http.MethodDelete,
+			Path:         "/notifications/:id",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				notification, err := app.DB().GetNotificationById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, NotificationNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = app.DB().RemoveNotification(ctx, notification.Id)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+	)
+}
@@ -0,0 +1,451 @@
+package apis
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+)
+
+const (
+	opdsNavigationType  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	opdsAcquisitionType = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+
+	opdsRelStream    = "http://vaemendis.net/opds-pse/stream"
+	opdsRelImage     = "http://opds-spec.org/image"
+	opdsRelThumbnail = "http://opds-spec.org/image/thumbnail"
+	opdsRelAcquire   = "http://opds-spec.org/acquisition"
+	opdsRelSortNew   = "http://opds-spec.org/sort/new"
+
+	opdsPerPage     = 50
+	opdsRecentLimit = 50
+)
+
+type opdsLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+
+	// NOTE(patrik): OPDS Page Streaming Extension
+	PseCount int `xml:"pse:count,attr,omitempty"`
+}
+
+type opdsContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type opdsEntry struct {
+	Id      string       `xml:"id"`
+	Title   string       `xml:"title"`
+	Updated string       `xml:"updated"`
+	Content *opdsContent `xml:"content,omitempty"`
+	Links   []opdsLink   `xml:"link"`
+}
+
+type opdsFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+
+	XmlnsOpds    string `xml:"xmlns:opds,attr"`
+	XmlnsPse     string `xml:"xmlns:pse,attr"`
+	XmlnsDcterms string `xml:"xmlns:dcterms,attr"`
+
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []opdsLink  `xml:"link"`
+	Entries []opdsEntry `xml:"entry"`
+}
+
+func newOpdsFeed(c pyrin.Context, id, title, self, kind string) opdsFeed {
+	return opdsFeed{
+		XmlnsOpds:    "http://opds-spec.org/2010/catalog",
+		XmlnsPse:     "http://vaemendis.net/opds-pse/ns",
+		XmlnsDcterms: "http://purl.org/dc/terms/",
+
+		Id:      id,
+		Title:   title,
+		Updated: opdsTime(time.Now()),
+		Links: []opdsLink{
+			{Rel: "self", Href: ConvertURL(c, self), Type: kind},
+			{Rel: "start", Href: ConvertURL(c, "/opds"), Type: opdsNavigationType},
+		},
+	}
+}
+
+func opdsTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func writeOpdsFeed(c pyrin.Context, feed opdsFeed, kind string) error {
+	w := c.Response()
+	w.Header().Set("Content-Type", kind+";charset=utf-8")
+
+	_, err := w.Write([]byte(xml.Header))
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	return enc.Encode(feed)
+}
+
+func convertOpdsCollection(c pyrin.Context, app core.App, collection database.Collection) (opdsEntry, error) {
+	pages, err := app.DB().CountCollectionPages(c.Request().Context(), collection.Id)
+	if err != nil {
+		return opdsEntry{}, err
+	}
+
+	entry := opdsEntry{
+		Id:      "urn:storebook:collection:" + collection.Id,
+		Title:   collection.Title,
+		Updated: opdsTime(time.UnixMilli(collection.Updated)),
+		Content: &opdsContent{
+			Type: "text",
+			Text: fmt.Sprintf("%d pages", pages),
+		},
+		Links: []opdsLink{
+			{
+				Rel:  opdsRelAcquire,
+				Href: ConvertURL(c, fmt.Sprintf("/opds/collections/%s/download", collection.Id)),
+				Type: "application/vnd.comicbook+zip",
+			},
+		},
+	}
+
+	if pages > 0 {
+		// NOTE(patrik): The client replaces {pageNumber} (starting at 0)
+		// and {maxWidth} inside the link
+		entry.Links = append(entry.Links, opdsLink{
+			Rel:      opdsRelStream,
+			Href:     ConvertURL(c, fmt.Sprintf("/opds/collections/%s/pages/{pageNumber}?width={maxWidth}", collection.Id)),
+			Type:     "image/jpeg",
+			PseCount: pages,
+		})
+	}
+
+	if cover := ConvertDBCollection(c, collection).Cover; cover != nil {
+		entry.Links = append(entry.Links,
+			opdsLink{Rel: opdsRelImage, Href: cover.Original},
+			opdsLink{Rel: opdsRelThumbnail, Href: cover.Medium, Type: "image/jpeg"},
+		)
+	}
+
+	return entry, nil
+}
+
+func InstallOpdsHandlers(app core.App, group pyrin.Group) {
+	auth := []echo.MiddlewareFunc{basicAuth(app)}
+
+	group.Register(
+		pyrin.NormalHandler{
+			Name:        "OpdsRoot",
+			Method:      http.MethodGet,
+			Path:        "",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				feed := newOpdsFeed(c, "urn:storebook:root", "storebook", "/opds", opdsNavigationType)
+
+				now := opdsTime(time.Now())
+				feed.Entries = []opdsEntry{
+					{
+						Id:      "urn:storebook:collections",
+						Title:   "All Collections",
+						Updated: now,
+						Content: &opdsContent{Type: "text", Text: "All the collections in the library"},
+						Links: []opdsLink{
+							{Rel: "subsection", Href: ConvertURL(c, "/opds/collections"), Type: opdsAcquisitionType},
+						},
+					},
+					{
+						Id:      "urn:storebook:series",
+						Title:   "Series",
+						Updated: now,
+						Content: &opdsContent{Type: "text", Text: "The collections grouped by series"},
+						Links: []opdsLink{
+							{Rel: "subsection", Href: ConvertURL(c, "/opds/series"), Type: opdsNavigationType},
+						},
+					},
+					{
+						Id:      "urn:storebook:recent",
+						Title:   "Recently Added",
+						Updated: now,
+						Content: &opdsContent{Type: "text", Text: "The latest collections added to the library"},
+						Links: []opdsLink{
+							{Rel: opdsRelSortNew, Href: ConvertURL(c, "/opds/recent"), Type: opdsAcquisitionType},
+						},
+					},
+				}
+
+				return writeOpdsFeed(c, feed, opdsNavigationType)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "OpdsCollections",
+			Method:      http.MethodGet,
+			Path:        "/collections",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				page := 0
+				if s := c.Request().URL.Query().Get("page"); s != "" {
+					page, _ = strconv.Atoi(s)
+					if page < 0 {
+						page = 0
+					}
+				}
+
+				collections, p, err := app.DB().GetPagedCollection(c.Request().Context(), database.CollectionFilter{}, database.FetchOptions{
+					PerPage: opdsPerPage,
+					Page:    page,
+				})
+				if err != nil {
+					return err
+				}
+
+				pageUrl := func(page int) string {
+					return ConvertURL(c, "/opds/collections?"+url.Values{"page": {strconv.Itoa(page)}}.Encode())
+				}
+
+				feed := newOpdsFeed(c, "urn:storebook:collections", "All Collections", "/opds/collections", opdsAcquisitionType)
+				feed.Links = append(feed.Links,
+					opdsLink{Rel: "up", Href: ConvertURL(c, "/opds"), Type: opdsNavigationType},
+					opdsLink{Rel: "first", Href: pageUrl(0), Type: opdsAcquisitionType},
+				)
+
+				if page > 0 {
+					feed.Links = append(feed.Links, opdsLink{Rel: "previous", Href: pageUrl(page - 1), Type: opdsAcquisitionType})
+				}
+
+				if page+1 < p.TotalPages {
+					feed.Links = append(feed.Links, opdsLink{Rel: "next", Href: pageUrl(page + 1), Type: opdsAcquisitionType})
+				}
+
+				if p.TotalPages > 0 {
+					feed.Links = append(feed.Links, opdsLink{Rel: "last", Href: pageUrl(p.TotalPages - 1), Type: opdsAcquisitionType})
+				}
+
+				for _, collection := range collections {
+					entry, err := convertOpdsCollection(c, app, collection)
+					if err != nil {
+						return err
+					}
+
+					feed.Entries = append(feed.Entries, entry)
+				}
+
+				return writeOpdsFeed(c, feed, opdsAcquisitionType)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "OpdsRecent",
+			Method:      http.MethodGet,
+			Path:        "/recent",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				collections, err := app.DB().GetRecentCollections(c.Request().Context(), opdsRecentLimit)
+				if err != nil {
+					return err
+				}
+
+				feed := newOpdsFeed(c, "urn:storebook:recent", "Recently Added", "/opds/recent", opdsAcquisitionType)
+				feed.Links = append(feed.Links, opdsLink{Rel: "up", Href: ConvertURL(c, "/opds"), Type: opdsNavigationType})
+
+				for _, collection := range collections {
+					entry, err := convertOpdsCollection(c, app, collection)
+					if err != nil {
+						return err
+					}
+
+					feed.Entries = append(feed.Entries, entry)
+				}
+
+				return writeOpdsFeed(c, feed, opdsAcquisitionType)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "OpdsSeries",
+			Method:      http.MethodGet,
+			Path:        "/series",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				series, err := app.DB().GetAllSeries(c.Request().Context())
+				if err != nil {
+					return err
+				}
+
+				feed := newOpdsFeed(c, "urn:storebook:series", "Series", "/opds/series", opdsNavigationType)
+				feed.Links = append(feed.Links, opdsLink{Rel: "up", Href: ConvertURL(c, "/opds"), Type: opdsNavigationType})
+
+				for _, s := range series {
+					// NOTE(patrik): The name is passed as a query parameter
+					// because it can contain any character
+					feed.Entries = append(feed.Entries, opdsEntry{
+						Id:      "urn:storebook:series:" + url.QueryEscape(s.Name),
+						Title:   s.Name,
+						Updated: opdsTime(time.UnixMilli(s.Updated)),
+						Content: &opdsContent{Type: "text", Text: fmt.Sprintf("%d collections", s.Count)},
+						Links: []opdsLink{
+							{
+								Rel:  "subsection",
+								Href: ConvertURL(c, "/opds/series/collections?"+url.Values{"name": {s.Name}}.Encode()),
+								Type: opdsAcquisitionType,
+							},
+						},
+					})
+				}
+
+				return writeOpdsFeed(c, feed, opdsNavigationType)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "OpdsSeriesCollections",
+			Method:      http.MethodGet,
+			Path:        "/series/collections",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				name := c.Request().URL.Query().Get("name")
+				if name == "" {
+					return pyrin.NoContentNotFound()
+				}
+
+				collections, err := app.DB().GetCollectionsBySeries(c.Request().Context(), name)
+				if err != nil {
+					return err
+				}
+
+				if len(collections) == 0 {
+					return pyrin.NoContentNotFound()
+				}
+
+				self := "/opds/series/collections?" + url.Values{"name": {name}}.Encode()
+
+				feed := newOpdsFeed(c, "urn:storebook:series:"+url.QueryEscape(name), name, self, opdsAcquisitionType)
+				feed.Links = append(feed.Links, opdsLink{Rel: "up", Href: ConvertURL(c, "/opds/series"), Type: opdsNavigationType})
+
+				for _, collection := range collections {
+					entry, err := convertOpdsCollection(c, app, collection)
+					if err != nil {
+						return err
+					}
+
+					feed.Entries = append(feed.Entries, entry)
+				}
+
+				return writeOpdsFeed(c, feed, opdsAcquisitionType)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "OpdsDownloadCollection",
+			Method:      http.MethodGet,
+			Path:        "/collections/:id/download",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				id := c.Param("id")
+
+				collection, err := app.DB().GetCollectionById(c.Request().Context(), id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return pyrin.NoContentNotFound()
+					}
+
+					return err
+				}
+
+				w := c.Response()
+				w.Header().Set("Content-Type", "application/vnd.comicbook+zip")
+				w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+					"filename": collection.Title + ".cbz",
+				}))
+
+				// NOTE(patrik): The archive is streamed so errors after this
+				// point can't be sent to the client
+				err = core.ExportCollection(c.Request().Context(), app, collection.Id, w, nil)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					app.Logger().Error("Failed to stream collection", "collectionId", collection.Id, "err", err)
+				}
+
+				return nil
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "OpdsGetPage",
+			Method:      http.MethodGet,
+			Path:        "/collections/:id/pages/:page",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				id := c.Param("id")
+
+				page, err := strconv.Atoi(c.Param("page"))
+				if err != nil || page < 0 {
+					return pyrin.NoContentNotFound()
+				}
+
+				collection, err := app.DB().GetCollectionById(c.Request().Context(), id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return pyrin.NoContentNotFound()
+					}
+
+					return err
+				}
+
+				images, err := app.DB().GetAllImagesByCollectionId(c.Request().Context(), collection.Id)
+				if err != nil {
+					return err
+				}
+
+				images = core.ReadingPages(images)
+				if page >= len(images) {
+					return pyrin.NoContentNotFound()
+				}
+
+				image := images[page]
+
+				// NOTE(patrik): Serve the smallest thumbnail that is still
+				// wide enough for the client
+				width, _ := strconv.Atoi(c.Request().URL.Query().Get("width"))
+				if width > 0 {
+					dir := app.WorkDir().CollectionDirById(image.CollectionId)
+
+					for _, size := range core.ThumbnailSizes {
+						if size.Width < width {
+							continue
+						}
+
+						name := core.ThumbnailFilename(image.Hash, size.Name)
+
+						_, err := os.Stat(path.Join(dir.Thumbnails(), name))
+						if err == nil {
+							return pyrin.ServeFile(c, os.DirFS(dir.Thumbnails()), name)
+						}
+
+						break
+					}
+				}
+
+				return serveImage(c, app, image)
+			},
+		},
+	)
+}
@@ -0,0 +1,382 @@
+package apis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+const (
+	opds2FeedType     = "application/opds+json"
+	opds2ManifestType = "application/webpub+json"
+)
+
+type opds2Link struct {
+	Rel       string `json:"rel,omitempty"`
+	Href      string `json:"href"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+}
+
+type opds2FeedMetadata struct {
+	Title string `json:"title"`
+
+	NumberOfItems *int `json:"numberOfItems,omitempty"`
+	ItemsPerPage  *int `json:"itemsPerPage,omitempty"`
+	CurrentPage   *int `json:"currentPage,omitempty"`
+}
+
+type opds2PublicationMetadata struct {
+	Type       string `json:"@type,omitempty"`
+	Identifier string `json:"identifier"`
+	Title      string `json:"title"`
+	Modified   string `json:"modified"`
+
+	NumberOfPages      int    `json:"numberOfPages"`
+	ReadingProgression string `json:"readingProgression,omitempty"`
+}
+
+type opds2Publication struct {
+	Metadata opds2PublicationMetadata `json:"metadata"`
+	Links    []opds2Link              `json:"links"`
+	Images   []opds2Link              `json:"images,omitempty"`
+}
+
+type opds2Feed struct {
+	Metadata     opds2FeedMetadata  `json:"metadata"`
+	Links        []opds2Link        `json:"links"`
+	Navigation   []opds2Link        `json:"navigation,omitempty"`
+	Publications []opds2Publication `json:"publications,omitempty"`
+}
+
+type opds2Manifest struct {
+	Context string `json:"@context"`
+
+	Metadata     opds2PublicationMetadata `json:"metadata"`
+	Links        []opds2Link              `json:"links"`
+	ReadingOrder []opds2Link              `json:"readingOrder"`
+	Resources    []opds2Link              `json:"resources,omitempty"`
+}
+
+func writeOpds2(c pyrin.Context, contentType string, v any) error {
+	w := c.Response()
+	w.Header().Set("Content-Type", contentType)
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+func newOpds2Feed(c pyrin.Context, title, self string) opds2Feed {
+	return opds2Feed{
+		Metadata: opds2FeedMetadata{
+			Title: title,
+		},
+		Links: []opds2Link{
+			{Rel: "self", Href: ConvertURL(c, self), Type: opds2FeedType},
+			{Rel: "start", Href: ConvertURL(c, "/opds/v2"), Type: opds2FeedType},
+			{Rel: "search", Href: ConvertURL(c, "/opds/v2/collections") + "{?query}", Type: opds2FeedType, Templated: true},
+		},
+	}
+}
+
+// NOTE(patrik): OPDS 2.0 and Readium uses the same values as the reader
+// settings for the reading progression
+func opds2ReadingProgression(d types.ReadingDirection) string {
+	return string(d)
+}
+
+func convertOpds2Metadata(collection database.Collection, pages int, reader core.ReaderSettings) opds2PublicationMetadata {
+	return opds2PublicationMetadata{
+		Type:               "http://schema.org/ComicStory",
+		Identifier:         "urn:storebook:collection:" + collection.Id,
+		Title:              collection.Title,
+		Modified:           opdsTime(time.UnixMilli(collection.Updated)),
+		NumberOfPages:      pages,
+		ReadingProgression: opds2ReadingProgression(reader.Direction),
+	}
+}
+
+func opds2CoverImages(c pyrin.Context, collection database.Collection) []opds2Link {
+	cover := ConvertDBCollection(c, collection).Cover
+	if cover == nil {
+		return nil
+	}
+
+	contentType, _ := utils.ImageExtToContentType(strings.ToLower(path.Ext(cover.Original)))
+
+	images := []opds2Link{
+		{Href: cover.Original, Type: contentType},
+	}
+
+	for _, size := range core.ThumbnailSizes {
+		images = append(images, opds2Link{
+			Href:  thumbnailPath(cover, size.Name),
+			Type:  "image/jpeg",
+			Width: size.Width,
+		})
+	}
+
+	return images
+}
+
+func thumbnailPath(images *types.Images, size string) string {
+	switch size {
+	case "small":
+		return images.Small
+	case "medium":
+		return images.Medium
+	default:
+		return images.Large
+	}
+}
+
+func convertOpds2Publication(c pyrin.Context, app core.App, collection database.Collection, defaults core.ReaderSettings) (opds2Publication, error) {
+	pages, err := app.DB().CountCollectionPages(c.Request().Context(), collection.Id)
+	if err != nil {
+		return opds2Publication{}, err
+	}
+
+	reader := core.ResolveReaderSettings(defaults, collection)
+
+	return opds2Publication{
+		Metadata: convertOpds2Metadata(collection, pages, reader),
+		Links: []opds2Link{
+			{
+				Rel:  "self",
+				Href: ConvertURL(c, fmt.Sprintf("/opds/v2/collections/%s/manifest.json", collection.Id)),
+				Type: opds2ManifestType,
+			},
+			{
+				Rel:  opdsRelAcquire,
+				Href: ConvertURL(c, fmt.Sprintf("/opds/collections/%s/download", collection.Id)),
+				Type: "application/vnd.comicbook+zip",
+			},
+		},
+		Images: opds2CoverImages(c, collection),
+	}, nil
+}
+
+func InstallOpds2Handlers(app core.App, group pyrin.Group) {
+	auth := []echo.MiddlewareFunc{basicAuth(app)}
+
+	group.Register(
+		pyrin.NormalHandler{
+			Name:        "Opds2Root",
+			Method:      http.MethodGet,
+			Path:        "",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				feed := newOpds2Feed(c, "storebook", "/opds/v2")
+				feed.Navigation = []opds2Link{
+					{
+						Rel:   "subsection",
+						Href:  ConvertURL(c, "/opds/v2/collections"),
+						Type:  opds2FeedType,
+						Title: "All Collections",
+					},
+					{
+						Rel:   opdsRelSortNew,
+						Href:  ConvertURL(c, "/opds/v2/recent"),
+						Type:  opds2FeedType,
+						Title: "Recently Added",
+					},
+				}
+
+				return writeOpds2(c, opds2FeedType, feed)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "Opds2Collections",
+			Method:      http.MethodGet,
+			Path:        "/collections",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				ctx := c.Request().Context()
+
+				q := c.Request().URL.Query()
+				opts := getPageOptions(q)
+				opts.PerPage = opdsPerPage
+
+				filter := database.CollectionFilter{
+					Query: q.Get("query"),
+				}
+
+				collections, p, err := app.DB().GetPagedCollection(ctx, filter, opts)
+				if err != nil {
+					return err
+				}
+
+				defaults, err := core.GetReaderDefaults(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				pageUrl := func(page int) string {
+					v := url.Values{}
+					if filter.Query != "" {
+						v.Set("query", filter.Query)
+					}
+					v.Set("page", strconv.Itoa(page))
+
+					return "/opds/v2/collections?" + v.Encode()
+				}
+
+				title := "All Collections"
+				if filter.Query != "" {
+					title = fmt.Sprintf("Search: %s", filter.Query)
+				}
+
+				// NOTE(patrik): types.Page starts at page 0 while OPDS
+				// starts counting at 1
+				currentPage := p.Page + 1
+
+				feed := newOpds2Feed(c, title, pageUrl(p.Page))
+				feed.Metadata.NumberOfItems = &p.TotalItems
+				feed.Metadata.ItemsPerPage = &p.PerPage
+				feed.Metadata.CurrentPage = &currentPage
+
+				feed.Links = append(feed.Links, opds2Link{Rel: "first", Href: ConvertURL(c, pageUrl(0)), Type: opds2FeedType})
+
+				if p.Page > 0 {
+					feed.Links = append(feed.Links, opds2Link{Rel: "previous", Href: ConvertURL(c, pageUrl(p.Page-1)), Type: opds2FeedType})
+				}
+
+				if p.Page+1 < p.TotalPages {
+					feed.Links = append(feed.Links, opds2Link{Rel: "next", Href: ConvertURL(c, pageUrl(p.Page+1)), Type: opds2FeedType})
+				}
+
+				if p.TotalPages > 0 {
+					feed.Links = append(feed.Links, opds2Link{Rel: "last", Href: ConvertURL(c, pageUrl(p.TotalPages-1)), Type: opds2FeedType})
+				}
+
+				feed.Publications = make([]opds2Publication, 0, len(collections))
+				for _, collection := range collections {
+					publication, err := convertOpds2Publication(c, app, collection, defaults)
+					if err != nil {
+						return err
+					}
+
+					feed.Publications = append(feed.Publications, publication)
+				}
+
+				return writeOpds2(c, opds2FeedType, feed)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "Opds2Recent",
+			Method:      http.MethodGet,
+			Path:        "/recent",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				ctx := c.Request().Context()
+
+				collections, err := app.DB().GetRecentCollections(ctx, opdsRecentLimit)
+				if err != nil {
+					return err
+				}
+
+				defaults, err := core.GetReaderDefaults(ctx, app)
+				if err != nil {
+					return err
+				}
+
+				feed := newOpds2Feed(c, "Recently Added", "/opds/v2/recent")
+
+				feed.Publications = make([]opds2Publication, 0, len(collections))
+				for _, collection := range collections {
+					publication, err := convertOpds2Publication(c, app, collection, defaults)
+					if err != nil {
+						return err
+					}
+
+					feed.Publications = append(feed.Publications, publication)
+				}
+
+				return writeOpds2(c, opds2FeedType, feed)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "Opds2Manifest",
+			Method:      http.MethodGet,
+			Path:        "/collections/:id/manifest.json",
+			Middlewares: auth,
+			HandlerFunc: func(c pyrin.Context) error {
+				ctx := c.Request().Context()
+				id := c.Param("id")
+
+				collection, err := app.DB().GetCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return pyrin.NoContentNotFound()
+					}
+
+					return err
+				}
+
+				images, err := app.DB().GetAllImagesByCollectionId(ctx, collection.Id)
+				if err != nil {
+					return err
+				}
+
+				images = core.ReadingPages(images)
+
+				reader, err := core.GetCollectionReaderSettings(ctx, app, collection)
+				if err != nil {
+					return err
+				}
+
+				manifest := opds2Manifest{
+					Context:  "https://readium.org/webpub-manifest/context.jsonld",
+					Metadata: convertOpds2Metadata(collection, len(images), reader),
+					Links: []opds2Link{
+						{
+							Rel:  "self",
+							Href: ConvertURL(c, fmt.Sprintf("/opds/v2/collections/%s/manifest.json", collection.Id)),
+							Type: opds2ManifestType,
+						},
+					},
+					ReadingOrder: make([]opds2Link, 0, len(images)),
+				}
+
+				for i, image := range images {
+					contentType := image.MimeType.String
+					if !image.MimeType.Valid {
+						contentType, _ = utils.ImageExtToContentType(strings.ToLower(path.Ext(image.Filename)))
+					}
+
+					manifest.ReadingOrder = append(manifest.ReadingOrder, opds2Link{
+						Href:   ConvertURL(c, fmt.Sprintf("/opds/collections/%s/pages/%d", collection.Id, i)),
+						Type:   contentType,
+						Width:  int(image.Width.Int64),
+						Height: int(image.Height.Int64),
+					})
+				}
+
+				for _, image := range opds2CoverImages(c, collection) {
+					image.Rel = "cover"
+					manifest.Resources = append(manifest.Resources, image)
+				}
+
+				return writeOpds2(c, opds2ManifestType, manifest)
+			},
+		},
+	)
+}
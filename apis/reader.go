@@ -0,0 +1,136 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/pyrin/anvil"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+	"github.com/nanoteck137/validate"
+)
+
+type ReaderSettings struct {
+	Direction types.ReadingDirection `json:"direction"`
+	Layout    types.ReaderLayout     `json:"layout"`
+	Fit       types.ReaderFit        `json:"fit"`
+}
+
+func ConvertReaderSettings(s core.ReaderSettings) ReaderSettings {
+	return ReaderSettings{
+		Direction: s.Direction,
+		Layout:    s.Layout,
+		Fit:       s.Fit,
+	}
+}
+
+// NOTE(patrik): The values the collection has set itself, missing values
+// uses the reader defaults
+type ReaderOverrides struct {
+	Direction *string `json:"direction,omitempty"`
+	Layout    *string `json:"layout,omitempty"`
+	Fit       *string `json:"fit,omitempty"`
+}
+
+func ConvertReaderOverrides(collection database.Collection) ReaderOverrides {
+	return ReaderOverrides{
+		Direction: utils.SqlNullToStringPtr(collection.ReaderDirection),
+		Layout:    utils.SqlNullToStringPtr(collection.ReaderLayout),
+		Fit:       utils.SqlNullToStringPtr(collection.ReaderFit),
+	}
+}
+
+type GetReaderDefaults struct {
+	ReaderSettings
+}
+
+// NOTE(patrik): Set a value to an empty string to reset it to the built-in
+// default
+type UpdateReaderDefaultsBody struct {
+	Direction *string `json:"direction,omitempty"`
+	Layout    *string `json:"layout,omitempty"`
+	Fit       *string `json:"fit,omitempty"`
+}
+
+func (b *UpdateReaderDefaultsBody) Transform() {
+	b.Direction = anvil.StringPtr(b.Direction)
+	b.Layout = anvil.StringPtr(b.Layout)
+	b.Fit = anvil.StringPtr(b.Fit)
+}
+
+func (b UpdateReaderDefaultsBody) Validate() error {
+	return validate.ValidateStruct(&b,
+		validate.Field(&b.Direction, validate.By(types.ValidateReadingDirection)),
+		validate.Field(&b.Layout, validate.By(types.ValidateReaderLayout)),
+		validate.Field(&b.Fit, validate.By(types.ValidateReaderFit)),
+	)
+}
+
+func InstallReaderHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetReaderDefaults",
+			Method:       http.MethodGet,
+			Path:         "/reader/defaults",
+			ResponseType: GetReaderDefaults{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				defaults, err := core.GetReaderDefaults(c.Request().Context(), app)
+				if err != nil {
+					return nil, err
+				}
+
+				return GetReaderDefaults{
+					ReaderSettings: ConvertReaderSettings(defaults),
+				}, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "UpdateReaderDefaults",
+			Method:       http.MethodPatch,
+			Path:         "/reader/defaults",
+			ResponseType: nil,
+			BodyType:     UpdateReaderDefaultsBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				body, err := pyrin.Body[UpdateReaderDefaultsBody](c)
+				if err != nil {
+					return nil, err
+				}
+
+				ctx := context.Background()
+
+				update := func(key string, value *string) error {
+					if value == nil {
+						return nil
+					}
+
+					if *value == "" {
+						return app.DB().RemoveSetting(ctx, key)
+					}
+
+					return app.DB().SetSetting(ctx, key, *value)
+				}
+
+				err = update(core.SettingReaderDirection, body.Direction)
+				if err != nil {
+					return nil, err
+				}
+
+				err = update(core.SettingReaderLayout, body.Layout)
+				if err != nil {
+					return nil, err
+				}
+
+				err = update(core.SettingReaderFit, body.Fit)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+	)
+}
@@ -3,6 +3,8 @@ package apis
 import (
 	"net/http"
 	"os"
+	"path"
+	"strings"
 
 	"github.com/nanoteck137/pyrin"
 	"github.com/nanoteck137/storebook"
@@ -12,9 +14,24 @@ import (
 func RegisterHandlers(app core.App, router pyrin.Router) {
 	g := router.Group("/api/v1")
 	InstallSystemHandlers(app, g)
+	InstallTaskHandlers(app, g)
+	InstallBackupHandlers(app, g)
+	InstallStorageHandlers(app, g)
 	InstallAuthHandlers(app, g)
 
 	InstallCollectionHandlers(app, g)
+	InstallTrashHandlers(app, g)
+	InstallJobHandlers(app, g)
+	InstallNotificationHandlers(app, g)
+	InstallCalendarHandlers(app, g)
+	InstallDuplicateHandlers(app, g)
+	InstallReaderHandlers(app, g)
+
+	g = router.Group("/opds")
+	InstallOpdsHandlers(app, g)
+
+	g = router.Group("/opds/v2")
+	InstallOpds2Handlers(app, g)
 
 	g = router.Group("/files")
 	g.Register(
@@ -26,10 +43,72 @@ func RegisterHandlers(app core.App, router pyrin.Router) {
 				id := c.Param("id")
 				file := c.Param("file")
 
+				image, err := app.DB().GetImageByFilename(c.Request().Context(), id, file)
+				if err != nil {
+					return pyrin.NoContentNotFound()
+				}
+
+				return serveImage(c, app, image)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "GetCollectionCover",
+			Method:      http.MethodGet,
+			Path:        "/collections/:id/cover/:file",
+			HandlerFunc: func(c pyrin.Context) error {
+				id := c.Param("id")
+				file := c.Param("file")
+
+				collection, err := app.DB().GetCollectionById(c.Request().Context(), id)
+				if err != nil {
+					return pyrin.NoContentNotFound()
+				}
+
+				if !collection.CoverHash.Valid || collection.CoverFilename.String != file {
+					return pyrin.NoContentNotFound()
+				}
+
+				return serveBlob(c, app, core.CoverKey(collection), file)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "GetCollectionThumbnail",
+			Method:      http.MethodGet,
+			Path:        "/collections/:id/thumbnails/:file",
+			HandlerFunc: func(c pyrin.Context) error {
+				id := c.Param("id")
+				file := c.Param("file")
+
 				dir := app.WorkDir().CollectionDirById(id)
-				p := dir.Images()
-				f := os.DirFS(p)
 
+				_, err := os.Stat(path.Join(dir.Thumbnails(), file))
+				if err == nil {
+					return pyrin.ServeFile(c, os.DirFS(dir.Thumbnails()), file)
+				}
+
+				// NOTE(patrik): The thumbnail hasn't been generated yet so
+				// fallback to the original image
+				hash, _, _ := strings.Cut(file, ".")
+
+				image, err := app.DB().GetImageByHash(c.Request().Context(), id, hash)
+				if err != nil {
+					return pyrin.NoContentNotFound()
+				}
+
+				return serveImage(c, app, image)
+			},
+		},
+
+		pyrin.NormalHandler{
+			Name:        "GetExport",
+			Method:      http.MethodGet,
+			Path:        "/exports/:file",
+			HandlerFunc: func(c pyrin.Context) error {
+				file := c.Param("file")
+
+				f := os.DirFS(app.WorkDir().ExportsDir())
 				return pyrin.ServeFile(c, f, file)
 			},
 		},
@@ -44,5 +123,9 @@ func Server(app core.App) (*pyrin.Server, error) {
 		},
 	})
 
+	// NOTE(patrik): WebDAV is mounted on the server directly so the
+	// routes doesn't end up in the generated clients
+	InstallDavHandlers(app, s.Group("/dav"))
+
 	return s, nil
 }
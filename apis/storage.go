@@ -0,0 +1,48 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+)
+
+type GetStorageStats struct {
+	Blobs      int `json:"blobs"`
+	References int `json:"references"`
+
+	StoredBytes  int `json:"storedBytes"`
+	LogicalBytes int `json:"logicalBytes"`
+	SavedBytes   int `json:"savedBytes"`
+}
+
+func InstallStorageHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetStorageStats",
+			Method:       http.MethodGet,
+			Path:         "/system/storage",
+			ResponseType: GetStorageStats{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				stats, err := app.DB().GetBlobStats(context.TODO())
+				if err != nil {
+					return nil, err
+				}
+
+				return GetStorageStats{
+					Blobs:        stats.Blobs,
+					References:   stats.References,
+					StoredBytes:  int(stats.StoredSize),
+					LogicalBytes: int(stats.LogicalSize),
+					SavedBytes:   int(stats.LogicalSize - stats.StoredSize),
+				}, nil
+			},
+		},
+	)
+}
@@ -1,14 +1,18 @@
 package apis
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
-	"github.com/labstack/gommon/log"
 	"github.com/nanoteck137/pyrin"
 	"github.com/nanoteck137/storebook"
 	"github.com/nanoteck137/storebook/core"
 )
 
+const sseKeepAliveInterval = 30 * time.Second
+
 type GetSystemInfo struct {
 	Version string `json:"version"`
 }
@@ -18,76 +22,6 @@ type Event struct {
 	Data any    `json:"data"`
 }
 
-type EventData interface {
-	GetEventType() string
-}
-
-// NOTE(patrik): Based on: https://gist.github.com/Ananto30/8af841f250e89c07e122e2a838698246
-type Broker struct {
-	Notifier chan EventData
-
-	newClients     chan chan EventData
-	closingClients chan chan EventData
-	clients        map[chan EventData]bool
-}
-
-func NewServer() (broker *Broker) {
-	// Instantiate a broker
-	broker = &Broker{
-		Notifier:       make(chan EventData, 1),
-		newClients:     make(chan chan EventData),
-		closingClients: make(chan chan EventData),
-		clients:        make(map[chan EventData]bool),
-	}
-
-	// Set it running - listening and broadcasting events
-	go broker.listen()
-
-	return
-}
-
-func (broker *Broker) listen() {
-	for {
-		select {
-		case s := <-broker.newClients:
-			broker.clients[s] = true
-			log.Debug("Client added", "numClients", len(broker.clients))
-		case s := <-broker.closingClients:
-			delete(broker.clients, s)
-			log.Debug("Removed client", "numClients", len(broker.clients))
-		case event := <-broker.Notifier:
-			for clientMessageChan := range broker.clients {
-				clientMessageChan <- event
-			}
-		}
-	}
-}
-
-func (broker *Broker) EmitEvent(event EventData) {
-	broker.Notifier <- event
-}
-
-// const (
-// 	EventSyncing string = "syncing"
-// 	EventReport  string = "report"
-// )
-//
-// type SyncEvent struct {
-// 	Syncing bool `json:"syncing"`
-// }
-//
-// func (s SyncEvent) GetEventType() string {
-// 	return EventSyncing
-// }
-//
-// type ReportEvent struct {
-// 	Report
-// }
-//
-// func (s ReportEvent) GetEventType() string {
-// 	return EventReport
-// }
-
 func InstallSystemHandlers(app core.App, group pyrin.Group) {
 	group.Register(
 		pyrin.ApiHandler{
@@ -102,63 +36,90 @@ func InstallSystemHandlers(app core.App, group pyrin.Group) {
 			},
 		},
 
-		// pyrin.NormalHandler{
-		// 	Name:   "SseHandler",
-		// 	Method: http.MethodGet,
-		// 	Path:   "/system/library/sse",
-		// 	HandlerFunc: func(c pyrin.Context) error {
-		// 		r := c.Request()
-		// 		w := c.Response()
-		//
-		// 		w.Header().Set("Content-Type", "text/event-stream")
-		// 		w.Header().Set("Cache-Control", "no-cache")
-		// 		w.Header().Set("Connection", "keep-alive")
-		//
-		// 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		//
-		// 		rc := http.NewResponseController(w)
-		//
-		// 		eventChan := make(chan EventData)
-		// 		syncHandler.broker.newClients <- eventChan
-		//
-		// 		defer func() {
-		// 			syncHandler.broker.closingClients <- eventChan
-		// 		}()
-		//
-		// 		sendEvent := func(eventData EventData) {
-		// 			fmt.Fprintf(w, "data: ")
-		//
-		// 			event := Event{
-		// 				Type: eventData.GetEventType(),
-		// 				Data: eventData,
-		// 			}
-		//
-		// 			encode := json.NewEncoder(w)
-		// 			encode.Encode(event)
-		//
-		// 			fmt.Fprintf(w, "\n\n")
-		// 			rc.Flush()
-		// 		}
-		//
-		// 		sendEvent(SyncEvent{
-		// 			Syncing: syncHandler.isSyncing.Load(),
-		// 		})
-		//
-		// 		sendEvent(ReportEvent{
-		// 			Report: syncHandler.GetReport(),
-		// 		})
-		//
-		// 		for {
-		// 			select {
-		// 			case <-r.Context().Done():
-		// 				syncHandler.broker.closingClients <- eventChan
-		// 				return nil
-		//
-		// 			case event := <-eventChan:
-		// 				sendEvent(event)
-		// 			}
-		// 		}
-		// 	},
-		// },
+		pyrin.NormalHandler{
+			Name:   "SseHandler",
+			Method: http.MethodGet,
+			Path:   "/system/events",
+			HandlerFunc: func(c pyrin.Context) error {
+				r := c.Request()
+				w := c.Response()
+
+				// NOTE(patrik): EventSource can't set headers so allow the
+				// token to be passed as a query parameter
+				var err error
+				if token := r.URL.Query().Get("token"); token != "" {
+					err = checkToken(app, token)
+				} else {
+					err = LoggedIn(app, c)
+				}
+
+				if err != nil {
+					return err
+				}
+
+				w.Header().Set("Content-Type", "text/event-stream")
+				w.Header().Set("Cache-Control", "no-cache")
+				w.Header().Set("Connection", "keep-alive")
+
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+
+				rc := http.NewResponseController(w)
+
+				eventChan := app.Broker().Subscribe()
+				defer app.Broker().Unsubscribe(eventChan)
+
+				sendEvent := func(eventData core.EventData) error {
+					event := Event{
+						Type: eventData.GetEventType(),
+						Data: eventData,
+					}
+
+					data, err := json.Marshal(event)
+					if err != nil {
+						return err
+					}
+
+					_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+					if err != nil {
+						return err
+					}
+
+					return rc.Flush()
+				}
+
+				w.WriteHeader(http.StatusOK)
+				rc.Flush()
+
+				ticker := time.NewTicker(sseKeepAliveInterval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-r.Context().Done():
+						return nil
+
+					case <-ticker.C:
+						_, err := fmt.Fprint(w, ": keep-alive\n\n")
+						if err != nil {
+							return nil
+						}
+
+						rc.Flush()
+
+					case event, ok := <-eventChan:
+						// NOTE(patrik): The broker closed the channel
+						// because we were too slow
+						if !ok {
+							return nil
+						}
+
+						err := sendEvent(event)
+						if err != nil {
+							return nil
+						}
+					}
+				}
+			},
+		},
 	)
 }
@@ -0,0 +1,286 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+type TaskRun struct {
+	Id string `json:"id"`
+
+	Status types.JobStatus `json:"status"`
+	Error  *string         `json:"error,omitempty"`
+
+	Started  int64  `json:"started"`
+	Finished *int64 `json:"finished,omitempty"`
+}
+
+type Task struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Schedule    string `json:"schedule"`
+
+	Running bool   `json:"running"`
+	NextRun *int64 `json:"nextRun,omitempty"`
+
+	LastRun *TaskRun `json:"lastRun,omitempty"`
+}
+
+type GCDanglingImage struct {
+	CollectionId string `json:"collectionId"`
+	ImageId      string `json:"imageId"`
+	Filename     string `json:"filename"`
+}
+
+type RunGC struct {
+	DryRun bool `json:"dryRun"`
+
+	OrphanFiles    []string          `json:"orphanFiles"`
+	DanglingImages []GCDanglingImage `json:"danglingImages"`
+
+	UnreferencedBlobs int `json:"unreferencedBlobs"`
+
+	FreedBytes int `json:"freedBytes"`
+}
+
+type RunGCBody struct {
+	DryRun bool `json:"dryRun"`
+}
+
+type VerifyImagesBody struct {
+	Full bool `json:"full"`
+}
+
+type GetTasks struct {
+	Tasks []Task `json:"tasks"`
+}
+
+func ConvertDBTaskRun(run database.TaskRun) TaskRun {
+	var errStr *string
+	if run.Error.Valid {
+		errStr = &run.Error.String
+	}
+
+	var finished *int64
+	if run.Finished.Valid {
+		finished = &run.Finished.Int64
+	}
+
+	return TaskRun{
+		Id:       run.Id,
+		Status:   run.Status,
+		Error:    errStr,
+		Started:  run.Started,
+		Finished: finished,
+	}
+}
+
+func InstallTaskHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetTasks",
+			Method:       http.MethodGet,
+			Path:         "/system/tasks",
+			ResponseType: GetTasks{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				ctx := context.TODO()
+
+				infos := app.Scheduler().Tasks()
+
+				res := GetTasks{
+					Tasks: make([]Task, len(infos)),
+				}
+
+				for i, info := range infos {
+					task := Task{
+						Name:        info.Name,
+						Description: info.Description,
+						Schedule:    info.Schedule,
+						Running:     info.Running,
+					}
+
+					if info.NextRun != nil {
+						next := info.NextRun.UnixMilli()
+						task.NextRun = &next
+					}
+
+					run, err := app.DB().GetLastTaskRun(ctx, info.Name)
+					if err != nil {
+						if !errors.Is(err, database.ErrItemNotFound) {
+							return nil, err
+						}
+					} else {
+						lastRun := ConvertDBTaskRun(run)
+						task.LastRun = &lastRun
+					}
+
+					res.Tasks[i] = task
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:   "RunTask",
+			Method: http.MethodPost,
+			Path:   "/system/tasks/:name/run",
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				name := c.Param("name")
+
+				err = app.Scheduler().Trigger(name)
+				if err != nil {
+					if errors.Is(err, core.ErrTaskNotFound) {
+						return nil, TaskNotFound()
+					}
+
+					if errors.Is(err, core.ErrTaskRunning) {
+						return nil, TaskRunning()
+					}
+
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "RunGC",
+			Method:       http.MethodPost,
+			Path:         "/system/gc",
+			ResponseType: RunGC{},
+			BodyType:     RunGCBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				body, err := pyrin.Body[RunGCBody](c)
+				if err != nil {
+					return nil, err
+				}
+
+				report, err := core.CollectGarbage(context.TODO(), app, body.DryRun)
+				if err != nil {
+					return nil, err
+				}
+
+				res := RunGC{
+					DryRun:         report.DryRun,
+					OrphanFiles:    report.OrphanFiles,
+					DanglingImages: make([]GCDanglingImage, len(report.DanglingImages)),
+
+					UnreferencedBlobs: report.UnreferencedBlobs,
+
+					FreedBytes: int(report.FreedBytes),
+				}
+
+				if res.OrphanFiles == nil {
+					res.OrphanFiles = []string{}
+				}
+
+				for i, img := range report.DanglingImages {
+					res.DanglingImages[i] = GCDanglingImage{
+						CollectionId: img.CollectionId,
+						ImageId:      img.ImageId,
+						Filename:     img.Filename,
+					}
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "VerifyImages",
+			Method:       http.MethodPost,
+			Path:         "/system/verify",
+			ResponseType: CreateJob{},
+			BodyType:     VerifyImagesBody{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				body, err := pyrin.Body[VerifyImagesBody](c)
+				if err != nil {
+					return nil, err
+				}
+
+				jobId, err := app.Jobs().Enqueue(context.TODO(), core.JobTypeVerifyImages, core.VerifyImagesPayload{
+					Full: body.Full,
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "RehashImages",
+			Method:       http.MethodPost,
+			Path:         "/system/rehash",
+			ResponseType: CreateJob{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				jobId, err := app.Jobs().Enqueue(context.TODO(), core.JobTypeRehashImages, struct{}{})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "AnalyzeImages",
+			Method:       http.MethodPost,
+			Path:         "/system/analyze",
+			ResponseType: CreateJob{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				err := LoggedIn(app, c)
+				if err != nil {
+					return nil, err
+				}
+
+				jobId, err := app.Jobs().Enqueue(context.TODO(), core.JobTypeAnalyzeImages, struct{}{})
+				if err != nil {
+					return nil, err
+				}
+
+				return CreateJob{
+					JobId: jobId,
+				}, nil
+			},
+		},
+	)
+}
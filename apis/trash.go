@@ -0,0 +1,142 @@
+package apis
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/nanoteck137/pyrin"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+type TrashCollection struct {
+	Collection
+
+	Deleted int64  `json:"deleted"`
+	PurgeAt *int64 `json:"purgeAt,omitempty"`
+}
+
+type GetTrash struct {
+	Page        types.Page        `json:"page"`
+	Collections []TrashCollection `json:"collections"`
+}
+
+func ConvertDBTrashCollection(c pyrin.Context, app core.App, collection database.Collection) TrashCollection {
+	var purgeAt *int64
+
+	days := app.Config().TrashRetentionDays
+	if days > 0 {
+		deleted := time.UnixMilli(collection.Deleted.Int64)
+		t := deleted.Add(time.Duration(days) * 24 * time.Hour).UnixMilli()
+		purgeAt = &t
+	}
+
+	return TrashCollection{
+		Collection: ConvertDBCollection(c, collection),
+		Deleted:    collection.Deleted.Int64,
+		PurgeAt:    purgeAt,
+	}
+}
+
+func InstallTrashHandlers(app core.App, group pyrin.Group) {
+	group.Register(
+		pyrin.ApiHandler{
+			Name:         "GetTrash",
+			Method:       http.MethodGet,
+			Path:         "/trash",
+			ResponseType: GetTrash{},
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				q := c.Request().URL.Query()
+				opts := getPageOptions(q)
+
+				ctx := context.TODO()
+
+				collections, p, err := app.DB().GetPagedDeletedCollection(ctx, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				res := GetTrash{
+					Page:        p,
+					Collections: make([]TrashCollection, len(collections)),
+				}
+
+				for i, collection := range collections {
+					res.Collections[i] = ConvertDBTrashCollection(c, app, collection)
+				}
+
+				return res, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "RestoreCollection",
+			Method:       http.MethodPost,
+			Path:         "/trash/:id/restore",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				dbCollection, err := app.DB().GetDeletedCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = app.DB().UpdateCollection(ctx, dbCollection.Id, database.CollectionChanges{
+					Deleted: database.Change[sql.NullInt64]{
+						Value:   sql.NullInt64{},
+						Changed: true,
+					},
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				app.Broker().EmitEvent(core.CollectionEvent{
+					Type:         core.EventCollectionCreated,
+					CollectionId: dbCollection.Id,
+				})
+
+				return nil, nil
+			},
+		},
+
+		pyrin.ApiHandler{
+			Name:         "PurgeCollection",
+			Method:       http.MethodDelete,
+			Path:         "/trash/:id",
+			ResponseType: nil,
+			HandlerFunc: func(c pyrin.Context) (any, error) {
+				id := c.Param("id")
+
+				ctx := context.Background()
+
+				dbCollection, err := app.DB().GetDeletedCollectionById(ctx, id)
+				if err != nil {
+					if errors.Is(err, database.ErrItemNotFound) {
+						return nil, CollectionNotFound()
+					}
+
+					return nil, err
+				}
+
+				err = core.PurgeCollection(ctx, app, dbCollection.Id)
+				if err != nil {
+					return nil, err
+				}
+
+				return nil, nil
+			},
+		},
+	)
+}
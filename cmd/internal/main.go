@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/nanoteck137/pyrin/spark"
+	"github.com/nanoteck137/pyrin/spark/golang"
 	"github.com/nanoteck137/pyrin/spark/typescript"
 	"github.com/nanoteck137/pyrin/trail"
 	"github.com/nanoteck137/storebook/apis"
@@ -50,14 +51,14 @@ var genCmd = &cobra.Command{
 			}
 		}
 
-		// {
-		// 	gen := golang.GolangGenerator{}
-		//
-		// 	err = gen.Generate(&serverDef, resolver, "cmd/storebook-cli/api")
-		// 	if err != nil {
-		// 		logger.Fatal("failed to generate golang client", "err", err)
-		// 	}
-		// }
+		{
+			gen := golang.GolangGenerator{}
+
+			err = gen.Generate(&serverDef, resolver, "cmd/storebook-cli/api")
+			if err != nil {
+				logger.Fatal("failed to generate golang client", "err", err)
+			}
+		}
 	},
 }
 
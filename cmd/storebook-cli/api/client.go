@@ -0,0 +1,1044 @@
+// DO NOT EDIT THIS: This file was generated by the Pyrin Golang Generator
+package api
+
+
+func (c *Client) AnalyzeImages(options Options) (*CreateJob, error) {
+	path := "/api/v1/system/analyze"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, nil)
+}
+
+func (c *Client) CancelJob(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/jobs/%v/cancel", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) CreateBackup(body CreateBackupBody, options Options) (*CreateJob, error) {
+	path := "/api/v1/system/backups"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, body)
+}
+
+func (c *Client) CreateCollection(body CreateCollectionBody, options Options) (*CreateCollection, error) {
+	path := "/api/v1/collections"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateCollection](data, body)
+}
+
+func (c *Client) DeleteCollection(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "DELETE",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) DeleteCollectionImage(id string, imageId string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v", id, imageId)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "DELETE",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) DismissNotification(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/notifications/%v", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "DELETE",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) EditCollection(id string, body EditCollectionBody, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "PATCH",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, body)
+}
+
+func (c *Client) EditCollectionImage(id string, imageId string, body EditCollectionImageBody, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v", id, imageId)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "PATCH",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, body)
+}
+
+func (c *Client) ExportCollection(id string, options Options) (*CreateJob, error) {
+	path := Sprintf("/api/v1/collections/%v/export", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, nil)
+}
+
+func (c *Client) GenerateCollectionThumbnails(id string, options Options) (*CreateJob, error) {
+	path := Sprintf("/api/v1/collections/%v/thumbnails", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, nil)
+}
+
+func (c *Client) GetBackups(options Options) (*GetBackups, error) {
+	path := "/api/v1/system/backups"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetBackups](data, nil)
+}
+
+func (c *Client) GetCalendar(options Options) (*GetCalendar, error) {
+	path := "/api/v1/calendar"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetCalendar](data, nil)
+}
+
+func (c *Client) GetCollectionById(id string, options Options) (*GetCollectionById, error) {
+	path := Sprintf("/api/v1/collections/%v", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetCollectionById](data, nil)
+}
+
+
+func (c *Client) GetCollectionDuplicates(id string, options Options) (*GetCollectionDuplicates, error) {
+	path := Sprintf("/api/v1/collections/%v/duplicates", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetCollectionDuplicates](data, nil)
+}
+
+
+func (c *Client) GetCollectionImages(id string, options Options) (*GetCollectionImages, error) {
+	path := Sprintf("/api/v1/collections/%v/images", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetCollectionImages](data, nil)
+}
+
+
+func (c *Client) GetCollections(options Options) (*GetCollection, error) {
+	path := "/api/v1/collections"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetCollection](data, nil)
+}
+
+func (c *Client) GetDuplicateCollections(options Options) (*GetDuplicateCollections, error) {
+	path := "/api/v1/duplicates/collections"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetDuplicateCollections](data, nil)
+}
+
+
+func (c *Client) GetJobById(id string, options Options) (*GetJobById, error) {
+	path := Sprintf("/api/v1/jobs/%v", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetJobById](data, nil)
+}
+
+func (c *Client) GetJobs(options Options) (*GetJobs, error) {
+	path := "/api/v1/jobs"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetJobs](data, nil)
+}
+
+func (c *Client) GetNotifications(options Options) (*GetNotifications, error) {
+	path := "/api/v1/notifications"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetNotifications](data, nil)
+}
+
+func (c *Client) GetReaderDefaults(options Options) (*GetReaderDefaults, error) {
+	path := "/api/v1/reader/defaults"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetReaderDefaults](data, nil)
+}
+
+func (c *Client) GetStorageStats(options Options) (*GetStorageStats, error) {
+	path := "/api/v1/system/storage"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetStorageStats](data, nil)
+}
+
+func (c *Client) GetSystemInfo(options Options) (*GetSystemInfo, error) {
+	path := "/api/v1/system/info"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetSystemInfo](data, nil)
+}
+
+func (c *Client) GetTasks(options Options) (*GetTasks, error) {
+	path := "/api/v1/system/tasks"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetTasks](data, nil)
+}
+
+func (c *Client) GetTrash(options Options) (*GetTrash, error) {
+	path := "/api/v1/trash"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "GET",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[GetTrash](data, nil)
+}
+
+func (c *Client) MarkAllNotificationsRead(options Options) (*any, error) {
+	path := "/api/v1/notifications/read"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) MarkNotificationRead(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/notifications/%v/read", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) MarkNotificationUnread(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/notifications/%v/unread", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+
+
+
+
+
+
+
+
+
+
+
+func (c *Client) PurgeCollection(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/trash/%v", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "DELETE",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) RehashImages(options Options) (*CreateJob, error) {
+	path := "/api/v1/system/rehash"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, nil)
+}
+
+func (c *Client) ReplaceCollectionImage(id string, imageId string, boundary string, body Reader, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v", id, imageId)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "PUT",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return RequestForm[any](data, boundary, body)
+}
+
+func (c *Client) ResetCollectionCover(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v/cover", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "DELETE",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) RestoreCollection(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/trash/%v/restore", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) RetryJob(id string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/jobs/%v/retry", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) RunGC(body RunGCBody, options Options) (*RunGC, error) {
+	path := "/api/v1/system/gc"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[RunGC](data, body)
+}
+
+func (c *Client) RunTask(name string, options Options) (*any, error) {
+	path := Sprintf("/api/v1/system/tasks/%v/run", name)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, nil)
+}
+
+func (c *Client) SetCollectionCover(id string, body SetCollectionCoverBody, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v/cover", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "PATCH",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, body)
+}
+
+func (c *Client) Signin(body SigninBody, options Options) (*Signin, error) {
+	path := "/api/v1/auth/signin"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[Signin](data, body)
+}
+
+func (c *Client) SplitCollectionImage(id string, imageId string, options Options) (*SplitCollectionImage, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v/split", id, imageId)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[SplitCollectionImage](data, nil)
+}
+
+func (c *Client) SplitCollectionSpreads(id string, options Options) (*CreateJob, error) {
+	path := Sprintf("/api/v1/collections/%v/spreads/split", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, nil)
+}
+
+
+func (c *Client) UpdateReaderDefaults(body UpdateReaderDefaultsBody, options Options) (*any, error) {
+	path := "/api/v1/reader/defaults"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "PATCH",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[any](data, body)
+}
+
+func (c *Client) UploadCollectionCover(id string, boundary string, body Reader, options Options) (*any, error) {
+	path := Sprintf("/api/v1/collections/%v/cover", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return RequestForm[any](data, boundary, body)
+}
+
+func (c *Client) UploadToCollection(id string, boundary string, body Reader, options Options) (*UploadToCollection, error) {
+	path := Sprintf("/api/v1/collections/%v/upload", id)
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return RequestForm[UploadToCollection](data, boundary, body)
+}
+
+func (c *Client) VerifyImages(body VerifyImagesBody, options Options) (*CreateJob, error) {
+	path := "/api/v1/system/verify"
+	url, err := createUrl(c.addr, path, options.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	data := RequestData{
+		Url: url,
+		Method: "POST",
+		ClientHeaders: c.Headers,
+		Headers: options.Header,
+	}
+	return Request[CreateJob](data, body)
+}
+
+func (c *ClientUrls) AnalyzeImages() (*URL, error) {
+	path := "/api/v1/system/analyze"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) CancelJob(id string) (*URL, error) {
+	path := Sprintf("/api/v1/jobs/%v/cancel", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) CreateBackup() (*URL, error) {
+	path := "/api/v1/system/backups"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) CreateCollection() (*URL, error) {
+	path := "/api/v1/collections"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) DeleteCollection(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) DeleteCollectionImage(id string, imageId string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v", id, imageId)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) DismissNotification(id string) (*URL, error) {
+	path := Sprintf("/api/v1/notifications/%v", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) EditCollection(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) EditCollectionImage(id string, imageId string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v", id, imageId)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) ExportCollection(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/export", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GenerateCollectionThumbnails(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/thumbnails", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetBackups() (*URL, error) {
+	path := "/api/v1/system/backups"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCalendar() (*URL, error) {
+	path := "/api/v1/calendar"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollectionById(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollectionCover(id string, file string) (*URL, error) {
+	path := Sprintf("/files/collections/%v/cover/%v", id, file)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollectionDuplicates(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/duplicates", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollectionImage(id string, file string) (*URL, error) {
+	path := Sprintf("/files/collections/%v/images/%v", id, file)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollectionImages(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/images", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollectionThumbnail(id string, file string) (*URL, error) {
+	path := Sprintf("/files/collections/%v/thumbnails/%v", id, file)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetCollections() (*URL, error) {
+	path := "/api/v1/collections"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetDuplicateCollections() (*URL, error) {
+	path := "/api/v1/duplicates/collections"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetExport(file string) (*URL, error) {
+	path := Sprintf("/files/exports/%v", file)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetJobById(id string) (*URL, error) {
+	path := Sprintf("/api/v1/jobs/%v", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetJobs() (*URL, error) {
+	path := "/api/v1/jobs"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetNotifications() (*URL, error) {
+	path := "/api/v1/notifications"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetReaderDefaults() (*URL, error) {
+	path := "/api/v1/reader/defaults"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetStorageStats() (*URL, error) {
+	path := "/api/v1/system/storage"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetSystemInfo() (*URL, error) {
+	path := "/api/v1/system/info"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetTasks() (*URL, error) {
+	path := "/api/v1/system/tasks"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) GetTrash() (*URL, error) {
+	path := "/api/v1/trash"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) MarkAllNotificationsRead() (*URL, error) {
+	path := "/api/v1/notifications/read"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) MarkNotificationRead(id string) (*URL, error) {
+	path := Sprintf("/api/v1/notifications/%v/read", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) MarkNotificationUnread(id string) (*URL, error) {
+	path := Sprintf("/api/v1/notifications/%v/unread", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) Opds2Collections() (*URL, error) {
+	path := "/opds/v2/collections"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) Opds2Manifest(id string) (*URL, error) {
+	path := Sprintf("/opds/v2/collections/%v/manifest.json", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) Opds2Recent() (*URL, error) {
+	path := "/opds/v2/recent"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) Opds2Root() (*URL, error) {
+	path := "/opds/v2"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsCollections() (*URL, error) {
+	path := "/opds/collections"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsDownloadCollection(id string) (*URL, error) {
+	path := Sprintf("/opds/collections/%v/download", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsGetPage(id string, page string) (*URL, error) {
+	path := Sprintf("/opds/collections/%v/pages/%v", id, page)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsRecent() (*URL, error) {
+	path := "/opds/recent"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsRoot() (*URL, error) {
+	path := "/opds"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsSeries() (*URL, error) {
+	path := "/opds/series"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) OpdsSeriesCollections() (*URL, error) {
+	path := "/opds/series/collections"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) PurgeCollection(id string) (*URL, error) {
+	path := Sprintf("/api/v1/trash/%v", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) RehashImages() (*URL, error) {
+	path := "/api/v1/system/rehash"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) ReplaceCollectionImage(id string, imageId string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v", id, imageId)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) ResetCollectionCover(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/cover", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) RestoreCollection(id string) (*URL, error) {
+	path := Sprintf("/api/v1/trash/%v/restore", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) RetryJob(id string) (*URL, error) {
+	path := Sprintf("/api/v1/jobs/%v/retry", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) RunGC() (*URL, error) {
+	path := "/api/v1/system/gc"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) RunTask(name string) (*URL, error) {
+	path := Sprintf("/api/v1/system/tasks/%v/run", name)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) SetCollectionCover(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/cover", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) Signin() (*URL, error) {
+	path := "/api/v1/auth/signin"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) SplitCollectionImage(id string, imageId string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/images/%v/split", id, imageId)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) SplitCollectionSpreads(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/spreads/split", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) SseHandler() (*URL, error) {
+	path := "/api/v1/system/events"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) UpdateReaderDefaults() (*URL, error) {
+	path := "/api/v1/reader/defaults"
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) UploadCollectionCover(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/cover", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) UploadToCollection(id string) (*URL, error) {
+	path := Sprintf("/api/v1/collections/%v/upload", id)
+	return c.getUrl(path)
+}
+
+func (c *ClientUrls) VerifyImages() (*URL, error) {
+	path := "/api/v1/system/verify"
+	return c.getUrl(path)
+}
@@ -0,0 +1,597 @@
+// DO NOT EDIT THIS: This file was generated by the Pyrin Golang Generator
+package api
+
+// Name: Backup
+type Backup struct {
+	// Name: Backup.name
+	Name string `json:"name"`
+	// Name: Backup.id
+	Id string `json:"id"`
+	// Name: Backup.size
+	Size int `json:"size"`
+	// Name: Backup.incremental
+	Incremental bool `json:"incremental"`
+	// Name: Backup.baseId
+	BaseId *string `json:"baseId,omitempty"`
+	// Name: Backup.created
+	Created int `json:"created"`
+}
+
+// Name: CalendarRelease
+type CalendarRelease struct {
+	// Name: CalendarRelease.collectionId
+	CollectionId string `json:"collectionId"`
+	// Name: CalendarRelease.title
+	Title string `json:"title"`
+	// Name: CalendarRelease.part
+	Part int `json:"part"`
+	// Name: CalendarRelease.date
+	Date string `json:"date"`
+}
+
+// Name: CalendarSeason
+type CalendarSeason struct {
+	// Name: CalendarSeason.season
+	Season string `json:"season"`
+	// Name: CalendarSeason.releases
+	Releases []CalendarRelease `json:"releases"`
+}
+
+// Name: Images
+type Images struct {
+	// Name: Images.original
+	Original string `json:"original"`
+	// Name: Images.small
+	Small string `json:"small"`
+	// Name: Images.medium
+	Medium string `json:"medium"`
+	// Name: Images.large
+	Large string `json:"large"`
+}
+
+// Name: CollectionRelease
+type CollectionRelease struct {
+	// Name: CollectionRelease.start
+	Start string `json:"start"`
+	// Name: CollectionRelease.delayDays
+	DelayDays int `json:"delayDays"`
+	// Name: CollectionRelease.intervalDays
+	IntervalDays int `json:"intervalDays"`
+	// Name: CollectionRelease.numParts
+	NumParts int `json:"numParts"`
+	// Name: CollectionRelease.status
+	Status string `json:"status"`
+	// Name: CollectionRelease.currentPart
+	CurrentPart int `json:"currentPart"`
+	// Name: CollectionRelease.nextPart
+	NextPart *int `json:"nextPart,omitempty"`
+	// Name: CollectionRelease.nextRelease
+	NextRelease *string `json:"nextRelease,omitempty"`
+}
+
+// Name: Collection
+type Collection struct {
+	// Name: Collection.id
+	Id string `json:"id"`
+	// Name: Collection.title
+	Title string `json:"title"`
+	// Name: Collection.series
+	Series *string `json:"series,omitempty"`
+	// Name: Collection.volume
+	Volume *int `json:"volume,omitempty"`
+	// Name: Collection.cover
+	Cover *Images `json:"cover,omitempty"`
+	// Name: Collection.coverImageId
+	CoverImageId *string `json:"coverImageId,omitempty"`
+	// Name: Collection.customCover
+	CustomCover bool `json:"customCover"`
+	// Name: Collection.release
+	Release *CollectionRelease `json:"release,omitempty"`
+}
+
+// Name: CollectionImage
+type CollectionImage struct {
+	// Name: CollectionImage.id
+	Id string `json:"id"`
+	// Name: CollectionImage.collectionId
+	CollectionId string `json:"collectionId"`
+	// Name: CollectionImage.hash
+	Hash string `json:"hash"`
+	// Name: CollectionImage.filename
+	Filename string `json:"filename"`
+	// Name: CollectionImage.position
+	Position int `json:"position"`
+	// Name: CollectionImage.url
+	Url string `json:"url"`
+	// Name: CollectionImage.width
+	Width *int `json:"width,omitempty"`
+	// Name: CollectionImage.height
+	Height *int `json:"height,omitempty"`
+	// Name: CollectionImage.size
+	Size *int `json:"size,omitempty"`
+	// Name: CollectionImage.mimeType
+	MimeType *string `json:"mimeType,omitempty"`
+	// Name: CollectionImage.colorMode
+	ColorMode *string `json:"colorMode,omitempty"`
+	// Name: CollectionImage.animated
+	Animated *bool `json:"animated,omitempty"`
+	// Name: CollectionImage.spread
+	Spread bool `json:"spread"`
+	// Name: CollectionImage.split
+	Split bool `json:"split"`
+	// Name: CollectionImage.sourceImageId
+	SourceImageId *string `json:"sourceImageId,omitempty"`
+	// Name: CollectionImage.spreadSide
+	SpreadSide *string `json:"spreadSide,omitempty"`
+	// Name: CollectionImage.images
+	Images Images `json:"images"`
+}
+
+// Name: CreateBackupBody
+type CreateBackupBody struct {
+	// Name: CreateBackupBody.incremental
+	Incremental bool `json:"incremental"`
+}
+
+// Name: CreateCollection
+type CreateCollection struct {
+	// Name: CreateCollection.id
+	Id string `json:"id"`
+}
+
+// Name: CreateCollectionBody
+type CreateCollectionBody struct {
+	// Name: CreateCollectionBody.title
+	Title string `json:"title"`
+}
+
+// Name: CreateJob
+type CreateJob struct {
+	// Name: CreateJob.jobId
+	JobId string `json:"jobId"`
+}
+
+// Name: DuplicateCollection
+type DuplicateCollection struct {
+	// Name: DuplicateCollection.collection
+	Collection Collection `json:"collection"`
+	// Name: DuplicateCollection.matched
+	Matched int `json:"matched"`
+	// Name: DuplicateCollection.total
+	Total int `json:"total"`
+}
+
+// Name: DuplicateCollectionPair
+type DuplicateCollectionPair struct {
+	// Name: DuplicateCollectionPair.a
+	A DuplicateCollection `json:"a"`
+	// Name: DuplicateCollectionPair.b
+	B DuplicateCollection `json:"b"`
+	// Name: DuplicateCollectionPair.overlap
+	Overlap float32 `json:"overlap"`
+}
+
+// Name: DuplicateGroup
+type DuplicateGroup struct {
+	// Name: DuplicateGroup.images
+	Images []CollectionImage `json:"images"`
+}
+
+// Name: EditCollectionBody
+type EditCollectionBody struct {
+	// Name: EditCollectionBody.title
+	Title *string `json:"title,omitempty"`
+	// Name: EditCollectionBody.series
+	Series *string `json:"series,omitempty"`
+	// Name: EditCollectionBody.volume
+	Volume *int `json:"volume,omitempty"`
+	// Name: EditCollectionBody.releaseStart
+	ReleaseStart *string `json:"releaseStart,omitempty"`
+	// Name: EditCollectionBody.releaseDelayDays
+	ReleaseDelayDays *int `json:"releaseDelayDays,omitempty"`
+	// Name: EditCollectionBody.releaseIntervalDays
+	ReleaseIntervalDays *int `json:"releaseIntervalDays,omitempty"`
+	// Name: EditCollectionBody.releaseNumParts
+	ReleaseNumParts *int `json:"releaseNumParts,omitempty"`
+	// Name: EditCollectionBody.readerDirection
+	ReaderDirection *string `json:"readerDirection,omitempty"`
+	// Name: EditCollectionBody.readerLayout
+	ReaderLayout *string `json:"readerLayout,omitempty"`
+	// Name: EditCollectionBody.readerFit
+	ReaderFit *string `json:"readerFit,omitempty"`
+}
+
+// Name: EditCollectionImageBody
+type EditCollectionImageBody struct {
+	// Name: EditCollectionImageBody.position
+	Position *int `json:"position,omitempty"`
+}
+
+// Name: GCDanglingImage
+type GCDanglingImage struct {
+	// Name: GCDanglingImage.collectionId
+	CollectionId string `json:"collectionId"`
+	// Name: GCDanglingImage.imageId
+	ImageId string `json:"imageId"`
+	// Name: GCDanglingImage.filename
+	Filename string `json:"filename"`
+}
+
+// Name: GetBackups
+type GetBackups struct {
+	// Name: GetBackups.backups
+	Backups []Backup `json:"backups"`
+}
+
+// Name: GetCalendar
+type GetCalendar struct {
+	// Name: GetCalendar.start
+	Start string `json:"start"`
+	// Name: GetCalendar.end
+	End string `json:"end"`
+	// Name: GetCalendar.seasons
+	Seasons []CalendarSeason `json:"seasons"`
+}
+
+// Name: Page
+type Page struct {
+	// Name: Page.page
+	Page int `json:"page"`
+	// Name: Page.perPage
+	PerPage int `json:"perPage"`
+	// Name: Page.totalItems
+	TotalItems int `json:"totalItems"`
+	// Name: Page.totalPages
+	TotalPages int `json:"totalPages"`
+}
+
+// Name: GetCollection
+type GetCollection struct {
+	// Name: GetCollection.page
+	Page Page `json:"page"`
+	// Name: GetCollection.collections
+	Collections []Collection `json:"collections"`
+}
+
+// Name: ReaderSettings
+type ReaderSettings struct {
+	// Name: ReaderSettings.direction
+	Direction string `json:"direction"`
+	// Name: ReaderSettings.layout
+	Layout string `json:"layout"`
+	// Name: ReaderSettings.fit
+	Fit string `json:"fit"`
+}
+
+// Name: ReaderOverrides
+type ReaderOverrides struct {
+	// Name: ReaderOverrides.direction
+	Direction *string `json:"direction,omitempty"`
+	// Name: ReaderOverrides.layout
+	Layout *string `json:"layout,omitempty"`
+	// Name: ReaderOverrides.fit
+	Fit *string `json:"fit,omitempty"`
+}
+
+// Name: GetCollectionById
+type GetCollectionById struct {
+	// Name: GetCollectionById.id
+	Id string `json:"id"`
+	// Name: GetCollectionById.title
+	Title string `json:"title"`
+	// Name: GetCollectionById.series
+	Series *string `json:"series,omitempty"`
+	// Name: GetCollectionById.volume
+	Volume *int `json:"volume,omitempty"`
+	// Name: GetCollectionById.cover
+	Cover *Images `json:"cover,omitempty"`
+	// Name: GetCollectionById.coverImageId
+	CoverImageId *string `json:"coverImageId,omitempty"`
+	// Name: GetCollectionById.customCover
+	CustomCover bool `json:"customCover"`
+	// Name: GetCollectionById.release
+	Release *CollectionRelease `json:"release,omitempty"`
+	// Name: GetCollectionById.reader
+	Reader ReaderSettings `json:"reader"`
+	// Name: GetCollectionById.readerOverrides
+	ReaderOverrides ReaderOverrides `json:"readerOverrides"`
+}
+
+// Name: GetCollectionDuplicates
+type GetCollectionDuplicates struct {
+	// Name: GetCollectionDuplicates.threshold
+	Threshold int `json:"threshold"`
+	// Name: GetCollectionDuplicates.groups
+	Groups []DuplicateGroup `json:"groups"`
+}
+
+// Name: GetCollectionImages
+type GetCollectionImages struct {
+	// Name: GetCollectionImages.images
+	Images []CollectionImage `json:"images"`
+}
+
+// Name: GetDuplicateCollections
+type GetDuplicateCollections struct {
+	// Name: GetDuplicateCollections.threshold
+	Threshold int `json:"threshold"`
+	// Name: GetDuplicateCollections.minOverlap
+	MinOverlap float32 `json:"minOverlap"`
+	// Name: GetDuplicateCollections.pairs
+	Pairs []DuplicateCollectionPair `json:"pairs"`
+}
+
+// Name: JobLog
+type JobLog struct {
+	// Name: JobLog.message
+	Message string `json:"message"`
+	// Name: JobLog.created
+	Created int `json:"created"`
+}
+
+// Name: GetJobById
+type GetJobById struct {
+	// Name: GetJobById.id
+	Id string `json:"id"`
+	// Name: GetJobById.type
+	Type string `json:"type"`
+	// Name: GetJobById.status
+	Status string `json:"status"`
+	// Name: GetJobById.payload
+	Payload string `json:"payload"`
+	// Name: GetJobById.result
+	Result *string `json:"result,omitempty"`
+	// Name: GetJobById.error
+	Error *string `json:"error,omitempty"`
+	// Name: GetJobById.progressCurrent
+	ProgressCurrent int `json:"progressCurrent"`
+	// Name: GetJobById.progressTotal
+	ProgressTotal int `json:"progressTotal"`
+	// Name: GetJobById.attempts
+	Attempts int `json:"attempts"`
+	// Name: GetJobById.started
+	Started *int `json:"started,omitempty"`
+	// Name: GetJobById.finished
+	Finished *int `json:"finished,omitempty"`
+	// Name: GetJobById.created
+	Created int `json:"created"`
+	// Name: GetJobById.updated
+	Updated int `json:"updated"`
+	// Name: GetJobById.logs
+	Logs []JobLog `json:"logs"`
+}
+
+// Name: Job
+type Job struct {
+	// Name: Job.id
+	Id string `json:"id"`
+	// Name: Job.type
+	Type string `json:"type"`
+	// Name: Job.status
+	Status string `json:"status"`
+	// Name: Job.payload
+	Payload string `json:"payload"`
+	// Name: Job.result
+	Result *string `json:"result,omitempty"`
+	// Name: Job.error
+	Error *string `json:"error,omitempty"`
+	// Name: Job.progressCurrent
+	ProgressCurrent int `json:"progressCurrent"`
+	// Name: Job.progressTotal
+	ProgressTotal int `json:"progressTotal"`
+	// Name: Job.attempts
+	Attempts int `json:"attempts"`
+	// Name: Job.started
+	Started *int `json:"started,omitempty"`
+	// Name: Job.finished
+	Finished *int `json:"finished,omitempty"`
+	// Name: Job.created
+	Created int `json:"created"`
+	// Name: Job.updated
+	Updated int `json:"updated"`
+}
+
+// Name: GetJobs
+type GetJobs struct {
+	// Name: GetJobs.page
+	Page Page `json:"page"`
+	// Name: GetJobs.jobs
+	Jobs []Job `json:"jobs"`
+}
+
+// Name: Notification
+type Notification struct {
+	// Name: Notification.id
+	Id string `json:"id"`
+	// Name: Notification.type
+	Type string `json:"type"`
+	// Name: Notification.title
+	Title string `json:"title"`
+	// Name: Notification.message
+	Message string `json:"message"`
+	// Name: Notification.collectionId
+	CollectionId *string `json:"collectionId,omitempty"`
+	// Name: Notification.jobId
+	JobId *string `json:"jobId,omitempty"`
+	// Name: Notification.isRead
+	IsRead bool `json:"isRead"`
+	// Name: Notification.read
+	Read *int `json:"read,omitempty"`
+	// Name: Notification.created
+	Created int `json:"created"`
+}
+
+// Name: GetNotifications
+type GetNotifications struct {
+	// Name: GetNotifications.page
+	Page Page `json:"page"`
+	// Name: GetNotifications.unreadCount
+	UnreadCount int `json:"unreadCount"`
+	// Name: GetNotifications.notifications
+	Notifications []Notification `json:"notifications"`
+}
+
+// Name: GetReaderDefaults
+type GetReaderDefaults struct {
+	// Name: GetReaderDefaults.direction
+	Direction string `json:"direction"`
+	// Name: GetReaderDefaults.layout
+	Layout string `json:"layout"`
+	// Name: GetReaderDefaults.fit
+	Fit string `json:"fit"`
+}
+
+// Name: GetStorageStats
+type GetStorageStats struct {
+	// Name: GetStorageStats.blobs
+	Blobs int `json:"blobs"`
+	// Name: GetStorageStats.references
+	References int `json:"references"`
+	// Name: GetStorageStats.storedBytes
+	StoredBytes int `json:"storedBytes"`
+	// Name: GetStorageStats.logicalBytes
+	LogicalBytes int `json:"logicalBytes"`
+	// Name: GetStorageStats.savedBytes
+	SavedBytes int `json:"savedBytes"`
+}
+
+// Name: GetSystemInfo
+type GetSystemInfo struct {
+	// Name: GetSystemInfo.version
+	Version string `json:"version"`
+}
+
+// Name: TaskRun
+type TaskRun struct {
+	// Name: TaskRun.id
+	Id string `json:"id"`
+	// Name: TaskRun.status
+	Status string `json:"status"`
+	// Name: TaskRun.error
+	Error *string `json:"error,omitempty"`
+	// Name: TaskRun.started
+	Started int `json:"started"`
+	// Name: TaskRun.finished
+	Finished *int `json:"finished,omitempty"`
+}
+
+// Name: Task
+type Task struct {
+	// Name: Task.name
+	Name string `json:"name"`
+	// Name: Task.description
+	Description string `json:"description"`
+	// Name: Task.schedule
+	Schedule string `json:"schedule"`
+	// Name: Task.running
+	Running bool `json:"running"`
+	// Name: Task.nextRun
+	NextRun *int `json:"nextRun,omitempty"`
+	// Name: Task.lastRun
+	LastRun *TaskRun `json:"lastRun,omitempty"`
+}
+
+// Name: GetTasks
+type GetTasks struct {
+	// Name: GetTasks.tasks
+	Tasks []Task `json:"tasks"`
+}
+
+// Name: TrashCollection
+type TrashCollection struct {
+	// Name: TrashCollection.id
+	Id string `json:"id"`
+	// Name: TrashCollection.title
+	Title string `json:"title"`
+	// Name: TrashCollection.series
+	Series *string `json:"series,omitempty"`
+	// Name: TrashCollection.volume
+	Volume *int `json:"volume,omitempty"`
+	// Name: TrashCollection.cover
+	Cover *Images `json:"cover,omitempty"`
+	// Name: TrashCollection.coverImageId
+	CoverImageId *string `json:"coverImageId,omitempty"`
+	// Name: TrashCollection.customCover
+	CustomCover bool `json:"customCover"`
+	// Name: TrashCollection.release
+	Release *CollectionRelease `json:"release,omitempty"`
+	// Name: TrashCollection.deleted
+	Deleted int `json:"deleted"`
+	// Name: TrashCollection.purgeAt
+	PurgeAt *int `json:"purgeAt,omitempty"`
+}
+
+// Name: GetTrash
+type GetTrash struct {
+	// Name: GetTrash.page
+	Page Page `json:"page"`
+	// Name: GetTrash.collections
+	Collections []TrashCollection `json:"collections"`
+}
+
+// Name: RunGC
+type RunGC struct {
+	// Name: RunGC.dryRun
+	DryRun bool `json:"dryRun"`
+	// Name: RunGC.orphanFiles
+	OrphanFiles []string `json:"orphanFiles"`
+	// Name: RunGC.danglingImages
+	DanglingImages []GCDanglingImage `json:"danglingImages"`
+	// Name: RunGC.unreferencedBlobs
+	UnreferencedBlobs int `json:"unreferencedBlobs"`
+	// Name: RunGC.freedBytes
+	FreedBytes int `json:"freedBytes"`
+}
+
+// Name: RunGCBody
+type RunGCBody struct {
+	// Name: RunGCBody.dryRun
+	DryRun bool `json:"dryRun"`
+}
+
+// Name: SetCollectionCoverBody
+type SetCollectionCoverBody struct {
+	// Name: SetCollectionCoverBody.imageId
+	ImageId string `json:"imageId"`
+}
+
+// Name: Signin
+type Signin struct {
+	// Name: Signin.token
+	Token string `json:"token"`
+}
+
+// Name: SigninBody
+type SigninBody struct {
+	// Name: SigninBody.password
+	Password string `json:"password"`
+}
+
+// Name: SplitCollectionImage
+type SplitCollectionImage struct {
+	// Name: SplitCollectionImage.images
+	Images []CollectionImage `json:"images"`
+}
+
+// Name: UpdateReaderDefaultsBody
+type UpdateReaderDefaultsBody struct {
+	// Name: UpdateReaderDefaultsBody.direction
+	Direction *string `json:"direction,omitempty"`
+	// Name: UpdateReaderDefaultsBody.layout
+	Layout *string `json:"layout,omitempty"`
+	// Name: UpdateReaderDefaultsBody.fit
+	Fit *string `json:"fit,omitempty"`
+}
+
+// Name: UploadToCollection
+type UploadToCollection struct {
+	// Name: UploadToCollection.jobIds
+	JobIds []string `json:"jobIds"`
+}
+
+// Name: VerifyImagesBody
+type VerifyImagesBody struct {
+	// Name: VerifyImagesBody.full
+	Full bool `json:"full"`
+}
+
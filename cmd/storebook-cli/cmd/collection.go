@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/api"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [query]",
+	Short: "List the collections, the query searches the titles",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		page, _ := cmd.Flags().GetInt("page")
+		perPage, _ := cmd.Flags().GetInt("per-page")
+
+		client, _ := newClient(cmd)
+
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("perPage", strconv.Itoa(perPage))
+		if len(args) > 0 {
+			query.Set("query", args[0])
+		}
+
+		res, err := client.GetCollections(api.Options{
+			Query: query,
+		})
+		if err != nil {
+			logger.Fatal("Failed to get collections", "err", err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE\tSERIES\tVOLUME")
+
+		for _, collection := range res.Collections {
+			series := "-"
+			if collection.Series != nil {
+				series = *collection.Series
+			}
+
+			volume := "-"
+			if collection.Volume != nil {
+				volume = strconv.Itoa(*collection.Volume)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", collection.Id, collection.Title, series, volume)
+		}
+
+		w.Flush()
+
+		fmt.Printf("Page %d (%d pages, %d collections)\n", res.Page.Page, res.Page.TotalPages, res.Page.TotalItems)
+	},
+}
+
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit the metadata of a collection, only the flags set are changed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		var body api.EditCollectionBody
+
+		stringFlag := func(name string) *string {
+			if !cmd.Flags().Changed(name) {
+				return nil
+			}
+
+			v, _ := cmd.Flags().GetString(name)
+			return &v
+		}
+
+		intFlag := func(name string) *int {
+			if !cmd.Flags().Changed(name) {
+				return nil
+			}
+
+			v, _ := cmd.Flags().GetInt(name)
+			return &v
+		}
+
+		body.Title = stringFlag("title")
+		body.Series = stringFlag("series")
+		body.Volume = intFlag("volume")
+		body.ReleaseStart = stringFlag("release-start")
+		body.ReleaseDelayDays = intFlag("release-delay-days")
+		body.ReleaseIntervalDays = intFlag("release-interval-days")
+		body.ReleaseNumParts = intFlag("release-num-parts")
+		body.ReaderDirection = stringFlag("reader-direction")
+		body.ReaderLayout = stringFlag("reader-layout")
+		body.ReaderFit = stringFlag("reader-fit")
+
+		client, _ := newClient(cmd)
+
+		_, err := client.EditCollection(id, body, api.Options{})
+		if err != nil {
+			logger.Fatal("Failed to edit collection", "err", err)
+		}
+
+		res, err := client.GetCollectionById(id, api.Options{})
+		if err != nil {
+			logger.Fatal("Failed to get collection", "err", err)
+		}
+
+		fmt.Printf("Updated '%s' (%s)\n", res.Title, res.Id)
+	},
+}
+
+func init() {
+	listCmd.Flags().Int("page", 0, "Page to show, starts at 0")
+	listCmd.Flags().Int("per-page", 50, "Number of collections per page")
+
+	editCmd.Flags().String("title", "", "Title of the collection")
+	editCmd.Flags().String("series", "", "Series the collection belongs to, empty removes the series and the volume")
+	editCmd.Flags().Int("volume", 0, "Volume number inside the series")
+	editCmd.Flags().String("release-start", "", "Date of the first release (YYYY-MM-DD), empty removes the release schedule")
+	editCmd.Flags().Int("release-delay-days", 0, "Days before the first part is released")
+	editCmd.Flags().Int("release-interval-days", 0, "Days between the parts")
+	editCmd.Flags().Int("release-num-parts", 0, "Number of parts")
+	editCmd.Flags().String("reader-direction", "", "Reading direction (ltr, rtl or ttb), empty uses the reader default")
+	editCmd.Flags().String("reader-layout", "", "Reader layout (single, double or continuous), empty uses the reader default")
+	editCmd.Flags().String("reader-fit", "", "Reader fit (screen, width, height or original), empty uses the reader default")
+
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(editCmd)
+}
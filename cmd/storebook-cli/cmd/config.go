@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+
+	"github.com/nanoteck137/storebook"
+)
+
+type CliConfig struct {
+	Server string `json:"server"`
+	Token  string `json:"token"`
+}
+
+func cliConfigFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(dir, storebook.CliAppName, "config.json"), nil
+}
+
+func LoadCliConfig() (*CliConfig, error) {
+	p, err := cliConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	var conf CliConfig
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &conf, nil
+		}
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
+
+// NOTE(patrik): The file contains the token so only the user is allowed
+// to read it
+func (c *CliConfig) Save() error {
+	p, err := cliConfigFile()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(path.Dir(p), 0700)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p, data, 0600)
+}
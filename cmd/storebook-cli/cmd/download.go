@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/api"
+	"github.com/spf13/cobra"
+)
+
+type exportResult struct {
+	File string `json:"file"`
+}
+
+var downloadCmd = &cobra.Command{
+	Use:   "download <id>",
+	Short: "Download a collection as a CBZ archive",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+		output, _ := cmd.Flags().GetString("output")
+
+		client, _ := newClient(cmd)
+
+		// NOTE(patrik): The server writes the archive with a job and
+		// serves the finished file from the exports
+		res, err := client.ExportCollection(id, api.Options{})
+		if err != nil {
+			logger.Fatal("Failed to export collection", "err", err)
+		}
+
+		job, err := waitForJob(client, res.JobId)
+		if err != nil {
+			logger.Fatal("Failed to export collection", "err", err)
+		}
+
+		var result exportResult
+		if job.Result != nil {
+			err = json.Unmarshal([]byte(*job.Result), &result)
+			if err != nil {
+				logger.Fatal("Failed to parse export result", "err", err)
+			}
+		}
+
+		if result.File == "" {
+			logger.Fatal("Export didn't produce a file")
+		}
+
+		if output == "" {
+			output = result.File
+		}
+
+		u, err := client.Url.GetExport(result.File)
+		if err != nil {
+			logger.Fatal("Failed to create url", "err", err)
+		}
+
+		err = downloadFile(client, u.String(), output)
+		if err != nil {
+			logger.Fatal("Failed to download export", "err", err)
+		}
+
+		fmt.Printf("Downloaded %s\n", output)
+	},
+}
+
+func downloadFile(client *api.Client, u, output string) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header = client.Headers.Clone()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bar := newProgressBar(filepath.Base(output), resp.ContentLength)
+
+	_, err = io.Copy(f, bar.Reader(resp.Body))
+	bar.Finish()
+
+	if err == nil {
+		err = f.Close()
+	}
+
+	if err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	return nil
+}
+
+func init() {
+	downloadCmd.Flags().StringP("output", "o", "", "File to write the archive to (default is the name from the server)")
+
+	rootCmd.AddCommand(downloadCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/api"
+)
+
+const jobPollInterval = 500 * time.Millisecond
+
+// waitForJob polls the job until it's done, the job is returned even if
+// it failed
+func waitForJob(client *api.Client, id string) (*api.GetJobById, error) {
+	for {
+		job, err := client.GetJobById(id, api.Options{})
+		if err != nil {
+			return nil, err
+		}
+
+		switch job.Status {
+		case "success":
+			return job, nil
+		case "failed", "cancelled":
+			msg := job.Status
+			if job.Error != nil {
+				msg = *job.Error
+			}
+
+			return job, fmt.Errorf("job %s %s: %s", id, job.Status, msg)
+		}
+
+		time.Sleep(jobPollInterval)
+	}
+}
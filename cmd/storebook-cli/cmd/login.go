@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/api"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login <server>",
+	Short: "Sign in to the server and store the token",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		server := strings.TrimSuffix(args[0], "/")
+
+		password, _ := cmd.Flags().GetString("password")
+		if password == "" {
+			password = os.Getenv("STOREBOOK_PASSWORD")
+		}
+
+		if password == "" {
+			fmt.Fprint(os.Stderr, "Password: ")
+
+			// NOTE(patrik): Don't echo the password when typed in a
+			// terminal, piped passwords are read as a line
+			fd := int(os.Stdin.Fd())
+			if term.IsTerminal(fd) {
+				data, err := term.ReadPassword(fd)
+				fmt.Fprintln(os.Stderr)
+				if err != nil {
+					logger.Fatal("Failed to read password", "err", err)
+				}
+
+				password = string(data)
+			} else {
+				line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+				if err != nil && line == "" {
+					logger.Fatal("Failed to read password", "err", err)
+				}
+
+				password = strings.TrimRight(line, "\r\n")
+			}
+		}
+
+		client := api.New(server)
+		res, err := client.Signin(api.SigninBody{
+			Password: password,
+		}, api.Options{})
+		if err != nil {
+			logger.Fatal("Failed to sign in", "err", err)
+		}
+
+		conf, err := LoadCliConfig()
+		if err != nil {
+			logger.Fatal("Failed to load config", "err", err)
+		}
+
+		conf.Server = server
+		conf.Token = res.Token
+
+		err = conf.Save()
+		if err != nil {
+			logger.Fatal("Failed to save config", "err", err)
+		}
+
+		fmt.Printf("Signed in to %s\n", server)
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored token",
+	Run: func(cmd *cobra.Command, args []string) {
+		conf, err := LoadCliConfig()
+		if err != nil {
+			logger.Fatal("Failed to load config", "err", err)
+		}
+
+		conf.Token = ""
+
+		err = conf.Save()
+		if err != nil {
+			logger.Fatal("Failed to save config", "err", err)
+		}
+	},
+}
+
+func init() {
+	loginCmd.Flags().String("password", "", "Password to sign in with (default is $STOREBOOK_PASSWORD or a prompt)")
+
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+}
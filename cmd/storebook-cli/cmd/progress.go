@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const progressBarWidth = 30
+
+// progressBar draws a single line progress bar to stderr, total can be 0
+// when the size isn't known
+type progressBar struct {
+	label   string
+	total   int64
+	current int64
+
+	lastDraw time.Time
+}
+
+func newProgressBar(label string, total int64) *progressBar {
+	return &progressBar{
+		label: label,
+		total: total,
+	}
+}
+
+func (p *progressBar) Add(n int64) {
+	p.current += n
+
+	// NOTE(patrik): Redrawing for every chunk floods the terminal
+	if time.Since(p.lastDraw) >= 100*time.Millisecond {
+		p.draw()
+	}
+}
+
+func (p *progressBar) draw() {
+	p.lastDraw = time.Now()
+
+	if p.total <= 0 {
+		fmt.Fprintf(os.Stderr, "\r%s %s", p.label, formatBytes(p.current))
+		return
+	}
+
+	ratio := min(float64(p.current)/float64(p.total), 1)
+	filled := int(ratio * progressBarWidth)
+
+	fmt.Fprintf(
+		os.Stderr,
+		"\r%s [%s%s] %3d%% %s/%s",
+		p.label,
+		strings.Repeat("#", filled),
+		strings.Repeat("-", progressBarWidth-filled),
+		int(ratio*100),
+		formatBytes(p.current),
+		formatBytes(p.total),
+	)
+}
+
+func (p *progressBar) Finish() {
+	p.draw()
+	fmt.Fprintln(os.Stderr)
+}
+
+func (p *progressBar) Reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, bar: p}
+}
+
+type progressReader struct {
+	r   io.Reader
+	bar *progressBar
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.bar.Add(int64(n))
+	return n, err
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/nanoteck137/pyrin/trail"
+	"github.com/nanoteck137/storebook"
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/api"
+	"github.com/spf13/cobra"
+)
+
+var logger = trail.NewLogger(&trail.Options{Debug: true, Level: slog.LevelInfo})
+
+var rootCmd = &cobra.Command{
+	Use:     storebook.CliAppName,
+	Version: storebook.Version,
+}
+
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		logger.Fatal("Failed to run root command", "err", err)
+	}
+}
+
+// newClient creates a client for the server stored by the login command,
+// the --server flag overrides the stored server
+func newClient(cmd *cobra.Command) (*api.Client, *CliConfig) {
+	conf, err := LoadCliConfig()
+	if err != nil {
+		logger.Fatal("Failed to load config", "err", err)
+	}
+
+	server, _ := cmd.Flags().GetString("server")
+	if server != "" {
+		conf.Server = server
+	}
+
+	if conf.Server == "" {
+		logger.Fatal("No server set, run 'login' first")
+	}
+
+	client := api.New(conf.Server)
+	if conf.Token != "" {
+		client.Headers.Set("Authorization", "Bearer "+conf.Token)
+	}
+
+	return client, conf
+}
+
+func init() {
+	rootCmd.SetVersionTemplate(storebook.VersionTemplate(storebook.CliAppName))
+
+	rootCmd.PersistentFlags().String("server", "", "Server address (default is the server from login)")
+}
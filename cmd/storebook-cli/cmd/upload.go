@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/api"
+	"github.com/spf13/cobra"
+)
+
+func titleFromFilename(p string) string {
+	name := filepath.Base(p)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	return strings.TrimSpace(strings.ReplaceAll(name, "_", " "))
+}
+
+// uploadArchive streams the archive as a multipart form so large archives
+// doesn't have to be loaded into memory
+func uploadArchive(client *api.Client, collectionId, p string) (*api.UploadToCollection, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	bar := newProgressBar(filepath.Base(p), info.Size())
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+
+	go func() {
+		// NOTE(patrik): The server only accepts zip archives so the content
+		// type is set instead of the application/octet-stream that
+		// CreateFormFile uses
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     "file",
+			"filename": filepath.Base(p),
+		}))
+		header.Set("Content-Type", "application/zip")
+
+		part, err := w.CreatePart(header)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(part, bar.Reader(f))
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+
+		pw.CloseWithError(w.Close())
+	}()
+
+	res, err := client.UploadToCollection(collectionId, w.Boundary(), pr, api.Options{})
+	pr.Close()
+	bar.Finish()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+var uploadCmd = &cobra.Command{
+	Use:   "upload <archive...>",
+	Short: "Upload archives, each archive becomes a new collection unless --collection is set",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		collectionId, _ := cmd.Flags().GetString("collection")
+		noWait, _ := cmd.Flags().GetBool("no-wait")
+
+		client, _ := newClient(cmd)
+
+		failed := 0
+		for _, p := range args {
+			err := func() (err error) {
+				id := collectionId
+				if id == "" {
+					res, err := client.CreateCollection(api.CreateCollectionBody{
+						Title: titleFromFilename(p),
+					}, api.Options{})
+					if err != nil {
+						return err
+					}
+
+					id = res.Id
+				}
+
+				// NOTE(patrik): Don't leave an empty collection behind when
+				// the upload or the import fails, deleting only moves it to
+				// the trash so it's purged as well
+				defer func() {
+					if err != nil && collectionId == "" {
+						_, deleteErr := client.DeleteCollection(id, api.Options{})
+						if deleteErr == nil {
+							client.PurgeCollection(id, api.Options{})
+						}
+					}
+				}()
+
+				res, err := uploadArchive(client, id, p)
+				if err != nil {
+					return err
+				}
+
+				if len(res.JobIds) == 0 {
+					return fmt.Errorf("archive was not accepted by the server")
+				}
+
+				if noWait {
+					fmt.Printf("Uploaded %s to %s (job %s)\n", p, id, res.JobIds[0])
+					return nil
+				}
+
+				for _, jobId := range res.JobIds {
+					_, err := waitForJob(client, jobId)
+					if err != nil {
+						return err
+					}
+				}
+
+				fmt.Printf("Imported %s into %s\n", p, id)
+				return nil
+			}()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to upload %s: %v\n", p, err)
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	uploadCmd.Flags().String("collection", "", "Add the pages to an existing collection")
+	uploadCmd.Flags().Bool("no-wait", false, "Don't wait for the server to import the archives")
+
+	rootCmd.AddCommand(uploadCmd)
+}
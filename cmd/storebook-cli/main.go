@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/nanoteck137/storebook/cmd/storebook-cli/cmd"
+)
+
+func main() {
+	cmd.Execute()
+}
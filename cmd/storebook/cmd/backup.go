@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/nanoteck137/storebook/config"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create a backup of the database and the files as a tar.zst archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		incremental, _ := cmd.Flags().GetBool("incremental")
+		baseFile, _ := cmd.Flags().GetString("base")
+
+		app := core.NewBaseApp(&config.LoadedConfig)
+
+		err := app.Bootstrap()
+		if err != nil {
+			app.Logger().Fatal("Failed to bootstrap app", "err", err)
+		}
+
+		if output == "" {
+			output = app.WorkDir().BackupsDir()
+		}
+
+		err = os.MkdirAll(output, 0755)
+		if err != nil {
+			app.Logger().Fatal("Failed to create output directory", "err", err)
+		}
+
+		var base *core.BackupManifest
+		switch {
+		case baseFile != "":
+			manifest, err := core.ReadBackupManifest(baseFile)
+			if err != nil {
+				app.Logger().Fatal("Failed to read base backup", "err", err)
+			}
+
+			base = &manifest
+		case incremental:
+			manifest, err := core.LatestBackup(output)
+			if err != nil && !errors.Is(err, core.ErrNoBackups) {
+				app.Logger().Fatal("Failed to find latest backup", "err", err)
+			}
+
+			if err == nil {
+				base = &manifest
+			} else {
+				fmt.Println("No previous backup found, creating a full backup")
+			}
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		p, res, err := core.WriteBackup(ctx, app, output, base)
+		if err != nil {
+			app.Logger().Fatal("Failed to create backup", "err", err)
+		}
+
+		fmt.Printf("Wrote %d files (%d bytes) to %s\n", res.Files, res.Bytes, p)
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <backup> [incremental...]",
+	Short: "Restore a full backup and the incremental backups made after it into an empty data directory",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := config.LoadedConfig.DataDir
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		count, err := core.RestoreBackup(ctx, dir, args)
+		if err != nil {
+			logger.Fatal("Failed to restore backup", "err", err)
+		}
+
+		fmt.Printf("Restored %d files into %s\n", count, dir)
+	},
+}
+
+func init() {
+	backupCmd.Flags().StringP("output", "o", "", "Directory to write the backup to (default \"<data_dir>/backups\")")
+	backupCmd.Flags().Bool("incremental", false, "Only include files changed since the latest backup inside the output directory")
+	backupCmd.Flags().String("base", "", "Backup file to base the incremental backup on")
+
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nanoteck137/storebook/config"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove orphan files and image rows with missing files",
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		app := core.NewBaseApp(&config.LoadedConfig)
+
+		err := app.Bootstrap()
+		if err != nil {
+			app.Logger().Fatal("Failed to bootstrap app", "err", err)
+		}
+
+		report, err := core.CollectGarbage(context.Background(), app, dryRun)
+		if err != nil {
+			app.Logger().Fatal("Failed to collect garbage", "err", err)
+		}
+
+		action := "Removed"
+		if dryRun {
+			action = "Would remove"
+		}
+
+		for _, file := range report.OrphanFiles {
+			fmt.Printf("%s orphan file: %s\n", action, file)
+		}
+
+		for _, img := range report.DanglingImages {
+			fmt.Printf("%s dangling image: %s/%s (%s)\n", action, img.CollectionId, img.ImageId, img.Filename)
+		}
+
+		fmt.Printf("%s %d files, %d unreferenced blobs and %d image rows (%d bytes)\n", action, len(report.OrphanFiles), report.UnreferencedBlobs, len(report.DanglingImages), report.FreedBytes)
+	},
+}
+
+func init() {
+	gcCmd.Flags().Bool("dry-run", false, "Only report what would be removed")
+
+	rootCmd.AddCommand(gcCmd)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/nanoteck137/storebook/config"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <dir>",
+	Short: "Import every archive and image folder inside the directory as collections",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		workers, _ := cmd.Flags().GetInt("workers")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		root, err := filepath.Abs(args[0])
+		if err != nil {
+			logger.Fatal("Failed to resolve directory", "err", err)
+		}
+
+		app := core.NewBaseApp(&config.LoadedConfig)
+
+		err = app.Bootstrap()
+		if err != nil {
+			app.Logger().Fatal("Failed to bootstrap app", "err", err)
+		}
+
+		items, err := core.ScanLibrary(root)
+		if err != nil {
+			app.Logger().Fatal("Failed to scan directory", "err", err)
+		}
+
+		if dryRun {
+			for _, item := range items {
+				volume := "-"
+				if item.Volume.Valid {
+					volume = fmt.Sprint(item.Volume.Int64)
+				}
+
+				fmt.Printf("%s: '%s' (series: '%s', volume: %s)\n", item.Path, item.Title, item.Series, volume)
+			}
+
+			fmt.Printf("Found %d items\n", len(items))
+			return
+		}
+
+		// NOTE(patrik): Interrupting lets the workers finish the current
+		// items, the rest is imported on the next run
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		current := 0
+		report := core.ImportLibrary(ctx, app, items, workers, func(res core.LibraryImportResult) {
+			current++
+
+			switch {
+			case res.Err != nil:
+				fmt.Printf("[%d/%d] Failed %s: %v\n", current, len(items), res.Item.Path, res.Err)
+			case res.Skipped:
+				fmt.Printf("[%d/%d] Skipped %s, already imported\n", current, len(items), res.Item.Path)
+			default:
+				fmt.Printf("[%d/%d] Imported %s as '%s' (%s, %d images)\n", current, len(items), res.Item.Path, res.Item.Title, res.CollectionId, res.Images)
+			}
+		})
+
+		if len(report.Failed) > 0 {
+			fmt.Println()
+			fmt.Println("Failed:")
+			for _, res := range report.Failed {
+				fmt.Printf("  %s: %v\n", res.Item.Path, res.Err)
+			}
+		}
+
+		fmt.Printf("Imported %d collections (%d images), skipped %d, failed %d, not processed %d\n", report.Imported, report.Images, report.Skipped, len(report.Failed), len(items)-current)
+
+		if len(report.Failed) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	importCmd.Flags().Int("workers", 4, "Number of items imported at the same time")
+	importCmd.Flags().Bool("dry-run", false, "Only list the items and the derived titles")
+
+	rootCmd.AddCommand(importCmd)
+}
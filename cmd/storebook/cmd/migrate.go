@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"context"
+	"strconv"
+
 	"github.com/nanoteck137/storebook"
 	"github.com/nanoteck137/storebook/config"
 	"github.com/nanoteck137/storebook/core"
@@ -8,45 +11,116 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// NOTE(patrik): Relative to the root of the repository, only used by the
+// dev commands, the rest uses the migrations embedded in the binary
+const migrationsDir = "./database/migrations"
+
 var migrateCmd = &cobra.Command{
-	Use: "migrate",
+	Use:   "migrate",
+	Short: "Manage the database migrations",
+}
+
+func migrateApp() *core.BaseApp {
+	conf := config.LoadedConfig
+	conf.RunMigrations = false
+	app := core.NewBaseApp(&conf)
+
+	err := app.Bootstrap()
+	if err != nil {
+		app.Logger().Fatal("Failed to bootstrap app", "err", err)
+	}
+
+	return app
+}
+
+// backupBeforeMigrate takes a snapshot of the database before a migration
+// that can drop data
+func backupBeforeMigrate(cmd *cobra.Command, app *core.BaseApp) {
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+	if noBackup {
+		return
+	}
+
+	p, err := core.BackupDatabase(context.Background(), app)
+	if err != nil {
+		app.Logger().Fatal("Failed to backup database", "err", err)
+	}
+
+	app.Logger().Info("Backed up database", "file", p)
 }
 
 var upCmd = &cobra.Command{
-	Use: "up",
+	Use:   "up",
+	Short: "Apply all the pending migrations",
 	Run: func(cmd *cobra.Command, args []string) {
-		conf := config.LoadedConfig
-		conf.RunMigrations = false
-		app := core.NewBaseApp(&conf)
+		app := migrateApp()
 
-		err := app.Bootstrap()
+		err := app.DB().RunMigrateUp()
 		if err != nil {
-			app.Logger().Fatal("Failed to bootstrap app", "err", err)
+			app.Logger().Fatal("Failed to run migrate up", "err", err)
 		}
+	},
+}
 
-		// err = app.DB().RunMigrateUp()
-		// if err != nil {
-		// 	log.Fatal("Failed to run migrate up", "err", err)
-		// }
+var upToCmd = &cobra.Command{
+	Use:   "up-to <VERSION>",
+	Short: "Apply the pending migrations up to and including the version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			logger.Fatal("Invalid version", "version", args[0], "err", err)
+		}
+
+		app := migrateApp()
+
+		err = app.DB().RunMigrateUpTo(version)
+		if err != nil {
+			app.Logger().Fatal("Failed to run migrate up-to", "err", err)
+		}
 	},
 }
 
 var downCmd = &cobra.Command{
-	Use: "down",
+	Use:   "down",
+	Short: "Roll back the latest applied migration",
 	Run: func(cmd *cobra.Command, args []string) {
-		conf := config.LoadedConfig
-		conf.RunMigrations = false
-		app := core.NewBaseApp(&conf)
+		app := migrateApp()
+
+		backupBeforeMigrate(cmd, app)
 
-		err := app.Bootstrap()
+		err := app.DB().RunMigrateDown()
 		if err != nil {
-			app.Logger().Fatal("Failed to bootstrap app", "err", err)
+			app.Logger().Fatal("Failed to run migrate down", "err", err)
 		}
+	},
+}
 
-		// err = app.DB().RunMigrateDown()
-		// if err != nil {
-		// 	log.Fatal("Failed to run migrate down", "err", err)
-		// }
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Roll back the latest applied migration and apply it again",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := migrateApp()
+
+		backupBeforeMigrate(cmd, app)
+
+		err := app.DB().RunMigrateRedo()
+		if err != nil {
+			app.Logger().Fatal("Failed to run migrate redo", "err", err)
+		}
+	},
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which migrations has been applied",
+	Run: func(cmd *cobra.Command, args []string) {
+		app := migrateApp()
+
+		err := app.DB().RunMigrateStatus()
+		if err != nil {
+			app.Logger().Fatal("Failed to get migration status", "err", err)
+		}
 	},
 }
 
@@ -59,7 +133,7 @@ var createCmd = &cobra.Command{
 
 		logger := storebook.DefaultLogger()
 
-		err := goose.Create(nil, "./migrations", name, "sql")
+		err := goose.Create(nil, migrationsDir, name, "sql")
 		if err != nil {
 			logger.Fatal("Failed to create migration", "err", err)
 		}
@@ -72,7 +146,7 @@ var fixCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		logger := storebook.DefaultLogger()
 
-		err := goose.Fix("./migrations")
+		err := goose.Fix(migrationsDir)
 		if err != nil {
 			logger.Fatal("Failed to fix migrations", "err", err)
 		}
@@ -80,8 +154,14 @@ var fixCmd = &cobra.Command{
 }
 
 func init() {
+	downCmd.Flags().Bool("no-backup", false, "Skip the database backup taken before the migration")
+	redoCmd.Flags().Bool("no-backup", false, "Skip the database backup taken before the migration")
+
 	migrateCmd.AddCommand(upCmd)
+	migrateCmd.AddCommand(upToCmd)
 	migrateCmd.AddCommand(downCmd)
+	migrateCmd.AddCommand(redoCmd)
+	migrateCmd.AddCommand(statusCmd)
 	migrateCmd.AddCommand(createCmd)
 	migrateCmd.AddCommand(fixCmd)
 
@@ -17,6 +17,16 @@ var serveCmd = &cobra.Command{
 			app.Logger().Fatal("Failed to bootstrap app", "err", err)
 		}
 
+		err = app.Jobs().Start(app.Config().JobWorkers)
+		if err != nil {
+			app.Logger().Fatal("Failed to start job runner", "err", err)
+		}
+
+		err = app.Scheduler().Start()
+		if err != nil {
+			app.Logger().Fatal("Failed to start scheduler", "err", err)
+		}
+
 		e, err := apis.Server(app)
 		if err != nil {
 			app.Logger().Fatal("Failed to create server", "err", err)
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nanoteck137/storebook/config"
+	"github.com/nanoteck137/storebook/core"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Re-hash the stored images and report corrupted or missing files",
+	Run: func(cmd *cobra.Command, args []string) {
+		full, _ := cmd.Flags().GetBool("full")
+
+		app := core.NewBaseApp(&config.LoadedConfig)
+
+		err := app.Bootstrap()
+		if err != nil {
+			app.Logger().Fatal("Failed to bootstrap app", "err", err)
+		}
+
+		report, err := core.VerifyImages(context.Background(), app, full, nil)
+		if err != nil {
+			app.Logger().Fatal("Failed to verify images", "err", err)
+		}
+
+		for _, issue := range report.Issues {
+			fmt.Printf("%s: %s/%s (%s) %s\n", issue.Type, issue.CollectionId, issue.ImageId, issue.Filename, issue.Detail)
+		}
+
+		fmt.Printf("Checked %d images, %d problems\n", report.Checked, len(report.Issues))
+
+		if len(report.Issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	verifyCmd.Flags().Bool("full", false, "Verify all images, not only the ones changed since the last run")
+
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -10,12 +10,58 @@ import (
 
 var logger = storebook.DefaultLogger()
 
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"`
+
+	// NOTE(patrik): Seconds the presigned URLs are valid for, 0 makes the
+	// server proxy the files instead of redirecting
+	PresignExpiry int `mapstructure:"presign_expiry"`
+}
+
+type WatchConfig struct {
+	Dir string `mapstructure:"dir"`
+
+	// NOTE(patrik): Where the archives are moved after the import,
+	// defaults to "processed" and "failed" inside the watch directory
+	ProcessedDir string `mapstructure:"processed_dir"`
+	FailedDir    string `mapstructure:"failed_dir"`
+}
+
 type Config struct {
 	RunMigrations bool   `mapstructure:"run_migrations"`
 	ListenAddr    string `mapstructure:"listen_addr"`
 	DataDir       string `mapstructure:"data_dir"`
 	Password      string `mapstructure:"password"`
 	JwtSecret     string `mapstructure:"jwt_secret"`
+	JobWorkers    int    `mapstructure:"job_workers"`
+
+	// NOTE(patrik): Algorithm used to hash new images, existing images
+	// keeps their algorithm until they are rehashed
+	HashAlgorithm types.HashAlgorithm `mapstructure:"hash_algorithm"`
+
+	// NOTE(patrik): Number of days a deleted collection stays in the trash
+	// before it's purged, 0 disables the automatic purge
+	TrashRetentionDays int `mapstructure:"trash_retention_days"`
+
+	// NOTE(patrik): Where the image blobs are stored, "local" or "s3"
+	Storage string   `mapstructure:"storage"`
+	S3      S3Config `mapstructure:"s3"`
+
+	// NOTE(patrik): Mounts a read-only WebDAV view of the library at /dav
+	WebDav bool `mapstructure:"webdav"`
+
+	// NOTE(patrik): Directories checked by the watch-import task for new
+	// archives to import as collections
+	Watch []WatchConfig `mapstructure:"watch"`
+
+	// NOTE(patrik): Overrides for the scheduled task schedules, task name
+	// to cron expression or "off"
+	Schedules map[string]string `mapstructure:"schedules"`
 }
 
 func (c *Config) WorkDir() types.WorkDir {
@@ -25,6 +71,14 @@ func (c *Config) WorkDir() types.WorkDir {
 func setDefaults() {
 	viper.SetDefault("run_migrations", "true")
 	viper.SetDefault("listen_addr", ":3000")
+	viper.SetDefault("trash_retention_days", 30)
+	viper.SetDefault("job_workers", 2)
+	viper.SetDefault("hash_algorithm", types.HashAlgorithmSHA256)
+	viper.SetDefault("storage", "local")
+	viper.SetDefault("webdav", false)
+	viper.SetDefault("s3.region", "us-east-1")
+	viper.SetDefault("s3.path_style", true)
+	viper.SetDefault("s3.presign_expiry", 3600)
 	viper.BindEnv("data_dir")
 	viper.BindEnv("initial_password")
 	viper.BindEnv("jwt_secret")
@@ -46,6 +100,20 @@ func validateConfig(config *Config) {
 	validate(config.DataDir == "", "data_dir needs to be set")
 	validate(config.Password == "", "password needs to be set")
 	validate(config.JwtSecret == "", "jwt_secret needs to be set")
+	validate(config.JobWorkers <= 0, "job_workers needs to be greater then 0")
+	validate(!types.IsValidHashAlgorithm(config.HashAlgorithm), "hash_algorithm needs to be md5 or sha256")
+	validate(config.Storage != "local" && config.Storage != "s3", "storage needs to be local or s3")
+	if config.Storage == "s3" {
+		validate(config.S3.Endpoint == "", "s3.endpoint needs to be set")
+		validate(config.S3.Bucket == "", "s3.bucket needs to be set")
+		validate(config.S3.AccessKey == "", "s3.access_key needs to be set")
+		validate(config.S3.SecretKey == "", "s3.secret_key needs to be set")
+		validate(config.S3.PresignExpiry < 0, "s3.presign_expiry needs to be positive")
+	}
+	validate(config.TrashRetentionDays < 0, "trash_retention_days needs to be positive")
+	for _, watch := range config.Watch {
+		validate(watch.Dir == "", "watch.dir needs to be set")
+	}
 
 	if hasError {
 		os.Exit(1)
@@ -90,6 +158,7 @@ func InitConfig() {
 	configCopy := LoadedConfig
 	configCopy.JwtSecret = hide(configCopy.JwtSecret)
 	configCopy.Password = hide(configCopy.Password)
+	configCopy.S3.SecretKey = hide(configCopy.S3.SecretKey)
 
 	logger.Debug("Current Config", "config", configCopy)
 
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+const JobTypeAnalyzeImages = "analyze-images"
+
+// ImageAnalysis is the data extracted from the image content
+type ImageAnalysis struct {
+	PHash sql.NullString
+
+	Width     sql.NullInt64
+	Height    sql.NullInt64
+	Size      sql.NullInt64
+	MimeType  sql.NullString
+	ColorMode sql.NullString
+	Animated  sql.NullBool
+}
+
+// NOTE(patrik): The returned analysis is partially filled even when an
+// error is returned, the size and sniffed mime type don't need the image
+// to be decodable
+func AnalyzeImage(data []byte) (ImageAnalysis, error) {
+	res := ImageAnalysis{
+		Size: sql.NullInt64{
+			Int64: int64(len(data)),
+			Valid: true,
+		},
+	}
+
+	contentType := http.DetectContentType(data)
+	if strings.HasPrefix(contentType, "image/") {
+		res.MimeType = sql.NullString{
+			String: contentType,
+			Valid:  true,
+		}
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return res, err
+	}
+
+	res.Width = sql.NullInt64{Int64: int64(config.Width), Valid: true}
+	res.Height = sql.NullInt64{Int64: int64(config.Height), Valid: true}
+	res.MimeType = sql.NullString{String: "image/" + format, Valid: true}
+	res.Animated = sql.NullBool{Bool: isAnimated(format, data), Valid: true}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return res, err
+	}
+
+	colorMode := imageColorMode(config.ColorModel)
+
+	// NOTE(patrik): Decoders use RGBA models for images without an alpha
+	// channel as well, so check if the image actually uses it
+	if colorMode == types.ColorModeRGBA {
+		if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+			colorMode = types.ColorModeRGB
+		}
+	}
+
+	if colorMode != "" {
+		res.ColorMode = sql.NullString{String: string(colorMode), Valid: true}
+	}
+
+	res.PHash = sql.NullString{
+		String: FormatPHash(DHash(img)),
+		Valid:  true,
+	}
+
+	return res, nil
+}
+
+func imageColorMode(model color.Model) types.ColorMode {
+	if _, ok := model.(color.Palette); ok {
+		return types.ColorModePaletted
+	}
+
+	switch model {
+	case color.GrayModel, color.Gray16Model:
+		return types.ColorModeGray
+	case color.YCbCrModel:
+		return types.ColorModeRGB
+	case color.RGBAModel, color.RGBA64Model,
+		color.NRGBAModel, color.NRGBA64Model,
+		color.NYCbCrAModel:
+		return types.ColorModeRGBA
+	case color.CMYKModel:
+		return types.ColorModeCMYK
+	}
+
+	return ""
+}
+
+func isAnimated(format string, data []byte) bool {
+	switch format {
+	case "gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return false
+		}
+
+		return len(g.Image) > 1
+	case "png":
+		// NOTE(patrik): APNG stores the animation control chunk before
+		// the first image data chunk
+		const headerSize = 8
+
+		offset := headerSize
+		for offset+8 <= len(data) {
+			length := int(binary.BigEndian.Uint32(data[offset:]))
+			chunkType := string(data[offset+4 : offset+8])
+
+			switch chunkType {
+			case "acTL":
+				return true
+			case "IDAT":
+				return false
+			}
+
+			// NOTE(patrik): length + type + data + crc
+			offset += 12 + length
+		}
+	case "webp":
+		// NOTE(patrik): Animated images use the extended format (VP8X)
+		// with the animation flag set
+		if len(data) >= 21 && string(data[12:16]) == "VP8X" {
+			return data[20]&0x02 != 0
+		}
+	}
+
+	return false
+}
+
+func (a ImageAnalysis) Changes() database.ImageChanges {
+	return database.ImageChanges{
+		PHash: database.Change[sql.NullString]{
+			Value:   a.PHash,
+			Changed: true,
+		},
+		Width: database.Change[sql.NullInt64]{
+			Value:   a.Width,
+			Changed: true,
+		},
+		Height: database.Change[sql.NullInt64]{
+			Value:   a.Height,
+			Changed: true,
+		},
+		Size: database.Change[sql.NullInt64]{
+			Value:   a.Size,
+			Changed: true,
+		},
+		MimeType: database.Change[sql.NullString]{
+			Value:   a.MimeType,
+			Changed: true,
+		},
+		ColorMode: database.Change[sql.NullString]{
+			Value:   a.ColorMode,
+			Changed: true,
+		},
+		Animated: database.Change[sql.NullBool]{
+			Value:   a.Animated,
+			Changed: true,
+		},
+	}
+}
+
+func analyzeStoredImage(ctx context.Context, app App, img database.Image) error {
+	r, _, err := OpenImage(ctx, app, img)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// NOTE(patrik): Store what could be extracted even if the image
+	// couldn't be decoded
+	analysis, analyzeErr := AnalyzeImage(data)
+
+	err = app.DB().UpdateImage(ctx, img.CollectionId, img.Id, analysis.Changes())
+	if err != nil {
+		return err
+	}
+
+	return analyzeErr
+}
+
+func analyzeImagesJob(job *JobContext) error {
+	app := job.App()
+
+	images, err := app.DB().GetImagesToAnalyze(job)
+	if err != nil {
+		return err
+	}
+
+	job.Log("Analyzing %d images", len(images))
+
+	failed := 0
+	for i, img := range images {
+		if err := job.Err(); err != nil {
+			return err
+		}
+
+		err := analyzeStoredImage(job, app, img)
+		if err != nil {
+			job.Log("Failed to analyze %s/%s: %v", img.CollectionId, img.Id, err)
+			failed++
+		}
+
+		job.SetProgress(i+1, len(images))
+	}
+
+	if failed > 0 {
+		job.Log("Failed to analyze %d images", failed)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/nanoteck137/pyrin/trail"
 	"github.com/nanoteck137/storebook/config"
 	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/storage"
 	"github.com/nanoteck137/storebook/types"
 )
 
@@ -17,5 +18,10 @@ type App interface {
 
 	WorkDir() types.WorkDir
 
+	Jobs() *JobRunner
+	Broker() *Broker
+	Scheduler() *Scheduler
+	Storage() storage.Storage
+
 	Bootstrap() error
 }
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nanoteck137/storebook/config"
+	"github.com/nanoteck137/storebook/types"
+)
+
+func newTestApp(t *testing.T) *BaseApp {
+	t.Helper()
+
+	app := NewBaseApp(&config.Config{
+		RunMigrations:      true,
+		DataDir:            t.TempDir(),
+		Password:           "password",
+		JwtSecret:          "secret",
+		JobWorkers:         1,
+		HashAlgorithm:      types.HashAlgorithmSHA256,
+		TrashRetentionDays: 30,
+		Storage:            "local",
+	})
+
+	err := app.Bootstrap()
+	if err != nil {
+		t.Fatalf("failed to bootstrap app: %v", err)
+	}
+
+	t.Cleanup(func() {
+		app.DB().Close()
+	})
+
+	return app
+}
@@ -0,0 +1,698 @@
+package core
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/nanoteck137/storebook"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+const JobTypeBackup = "backup"
+
+const (
+	// NOTE(patrik): Milliseconds are included so backups started right
+	// after each other doesn't end up with the same name
+	backupTimeFormat = "20060102-150405.000"
+
+	backupVersion      = 1
+	backupManifestName = "manifest.json"
+	backupDatabaseName = "data.db"
+	backupExt          = ".tar.zst"
+)
+
+var ErrInvalidBackup = errors.New("invalid backup")
+var ErrNoBackups = errors.New("no backups found")
+var ErrRestoreDirNotEmpty = errors.New("restore directory is not empty")
+
+// BackupManifest is the first entry inside a backup archive
+type BackupManifest struct {
+	Version    int    `json:"version"`
+	Id         string `json:"id"`
+	AppVersion string `json:"appVersion"`
+	Storage    string `json:"storage"`
+
+	// NOTE(patrik): Unix milliseconds of when the backup was started
+	Created int64 `json:"created"`
+
+	// NOTE(patrik): Set for incremental backups, the backup only contains
+	// the files changed after the base backup was created
+	BaseId      string `json:"baseId,omitempty"`
+	BaseCreated int64  `json:"baseCreated,omitempty"`
+}
+
+func (m BackupManifest) Incremental() bool {
+	return m.BaseId != ""
+}
+
+type BackupPayload struct {
+	// NOTE(patrik): Based on the latest backup inside the backups dir,
+	// falls back to a full backup when there are no backups
+	Incremental bool `json:"incremental"`
+}
+
+type BackupResult struct {
+	File  string `json:"file"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+type BackupInfo struct {
+	Name string
+	Size int64
+
+	Manifest BackupManifest
+}
+
+type backupDir struct {
+	name string
+	dir  string
+}
+
+// NOTE(patrik): The blobs are only included for the local storage, the
+// s3 bucket needs to be backed up separately
+func backupDirs(app App) []backupDir {
+	workDir := app.WorkDir()
+
+	dirs := []backupDir{
+		{name: "collections", dir: workDir.CollectionsDir()},
+	}
+
+	if app.Config().Storage == "local" {
+		dirs = append(dirs, backupDir{name: "blobs", dir: workDir.BlobsDir()})
+	}
+
+	return dirs
+}
+
+func writeBackupFile(tw *tar.Writer, name, p string, info fs.FileInfo) (int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return 0, err
+	}
+
+	header.Name = name
+
+	err = tw.WriteHeader(header)
+	if err != nil {
+		return 0, err
+	}
+
+	return io.Copy(tw, f)
+}
+
+// CreateBackup writes a snapshot of the database and the files as a tar
+// archive compressed with zstd to w, when base is set only the files
+// changed after the base backup are included. The database is always
+// included in full
+func CreateBackup(ctx context.Context, app App, w io.Writer, base *BackupManifest) (BackupManifest, BackupResult, error) {
+	manifest := BackupManifest{
+		Version:    backupVersion,
+		Id:         utils.CreateId(),
+		AppVersion: storebook.Version,
+		Storage:    app.Config().Storage,
+		Created:    time.Now().UnixMilli(),
+	}
+
+	var since time.Time
+	if base != nil {
+		manifest.BaseId = base.Id
+		manifest.BaseCreated = base.Created
+		since = time.UnixMilli(base.Created)
+	}
+
+	var res BackupResult
+
+	tmp, err := os.MkdirTemp("", "storebook-backup-*")
+	if err != nil {
+		return manifest, res, err
+	}
+	defer os.RemoveAll(tmp)
+
+	dbFile := path.Join(tmp, backupDatabaseName)
+	err = app.DB().Backup(ctx, dbFile)
+	if err != nil {
+		return manifest, res, err
+	}
+
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return manifest, res, err
+	}
+
+	tw := tar.NewWriter(zw)
+
+	res, err = writeBackupArchive(ctx, app, tw, manifest, dbFile, since)
+	if err == nil {
+		err = tw.Close()
+	}
+
+	// NOTE(patrik): Closing the encoder more then once writes the frame
+	// checksum again and makes the archive invalid for other decoders
+	closeErr := zw.Close()
+	if err != nil {
+		return manifest, res, err
+	}
+
+	if closeErr != nil {
+		return manifest, res, closeErr
+	}
+
+	return manifest, res, nil
+}
+
+func writeBackupArchive(ctx context.Context, app App, tw *tar.Writer, manifest BackupManifest, dbFile string, since time.Time) (BackupResult, error) {
+	var res BackupResult
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return res, err
+	}
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    backupManifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.UnixMilli(manifest.Created),
+	})
+	if err != nil {
+		return res, err
+	}
+
+	_, err = tw.Write(data)
+	if err != nil {
+		return res, err
+	}
+
+	info, err := os.Stat(dbFile)
+	if err != nil {
+		return res, err
+	}
+
+	n, err := writeBackupFile(tw, backupDatabaseName, dbFile, info)
+	if err != nil {
+		return res, err
+	}
+
+	res.Files++
+	res.Bytes += n
+
+	for _, dir := range backupDirs(app) {
+		err := filepath.WalkDir(dir.dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// NOTE(patrik): Skips the temporary files of the local storage
+			if strings.HasPrefix(d.Name(), ".") || !d.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				// NOTE(patrik): Removed after the directory was listed
+				if os.IsNotExist(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			if info.ModTime().Before(since) {
+				return nil
+			}
+
+			rel, err := filepath.Rel(dir.dir, p)
+			if err != nil {
+				return err
+			}
+
+			n, err := writeBackupFile(tw, path.Join(dir.name, filepath.ToSlash(rel)), p, info)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			res.Files++
+			res.Bytes += n
+
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// WriteBackup creates a backup inside the directory, returns the path to
+// the created file
+func WriteBackup(ctx context.Context, app App, dir string, base *BackupManifest) (string, BackupResult, error) {
+	kind := "full"
+	if base != nil {
+		kind = "incremental"
+	}
+
+	name := fmt.Sprintf("storebook-%s-%s%s", time.Now().Format(backupTimeFormat), kind, backupExt)
+	out := path.Join(dir, name)
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", BackupResult{}, err
+	}
+	defer f.Close()
+
+	_, res, err := CreateBackup(ctx, app, f, base)
+	if err == nil {
+		err = f.Close()
+	}
+
+	if err != nil {
+		os.Remove(out)
+		return "", BackupResult{}, err
+	}
+
+	res.File = name
+
+	return out, res, nil
+}
+
+// BackupDatabase writes a snapshot of only the database to the backups
+// dir, used before changes that can lose data like a down migration.
+// Returns the path to the snapshot
+func BackupDatabase(ctx context.Context, app App) (string, error) {
+	version, err := app.DB().MigrationVersion()
+	if err != nil {
+		return "", err
+	}
+
+	dir := app.WorkDir().BackupsDir()
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("data-%s-v%d.db", time.Now().Format(backupTimeFormat), version)
+	out := path.Join(dir, name)
+
+	err = app.DB().Backup(ctx, out)
+	if err != nil {
+		os.Remove(out)
+		return "", err
+	}
+
+	return out, nil
+}
+
+// ReadBackupManifest reads the manifest from the start of the backup
+func ReadBackupManifest(p string) (BackupManifest, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return BackupManifest{}, err
+	}
+	defer f.Close()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return BackupManifest{}, err
+	}
+	defer zr.Close()
+
+	return readBackupManifest(tar.NewReader(zr))
+}
+
+func readBackupManifest(tr *tar.Reader) (BackupManifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return BackupManifest{}, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+
+	if header.Name != backupManifestName {
+		return BackupManifest{}, fmt.Errorf("%w: missing manifest", ErrInvalidBackup)
+	}
+
+	var manifest BackupManifest
+	err = json.NewDecoder(tr).Decode(&manifest)
+	if err != nil {
+		return BackupManifest{}, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+
+	if manifest.Version != backupVersion {
+		return BackupManifest{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidBackup, manifest.Version)
+	}
+
+	return manifest, nil
+}
+
+// ListBackups returns the backups inside the directory, newest first
+func ListBackups(dir string) ([]BackupInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var res []BackupInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), backupExt) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		manifest, err := ReadBackupManifest(path.Join(dir, entry.Name()))
+		if err != nil {
+			// NOTE(patrik): Not a backup created by us or a backup that
+			// is still being written
+			continue
+		}
+
+		res = append(res, BackupInfo{
+			Name:     entry.Name(),
+			Size:     info.Size(),
+			Manifest: manifest,
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Manifest.Created > res[j].Manifest.Created
+	})
+
+	return res, nil
+}
+
+// LatestBackup returns the manifest of the newest backup inside the
+// directory
+func LatestBackup(dir string) (BackupManifest, error) {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return BackupManifest{}, err
+	}
+
+	if len(backups) == 0 {
+		return BackupManifest{}, ErrNoBackups
+	}
+
+	return backups[0].Manifest, nil
+}
+
+// validBackupName makes sure the entry stays inside the restore directory
+func validBackupName(name string) bool {
+	if path.Clean(name) != name || path.IsAbs(name) {
+		return false
+	}
+
+	return strings.HasPrefix(name, "collections/") || strings.HasPrefix(name, "blobs/")
+}
+
+// blobHasher returns a hasher for entries inside the blob store, the name
+// of a blob is the hash of its content
+func blobHasher(name string) (hash.Hash, string, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 || parts[0] != "blobs" {
+		return nil, "", nil
+	}
+
+	h, err := utils.NewHasher(types.HashAlgorithm(parts[1]))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %s: %w", ErrInvalidBackup, name, err)
+	}
+
+	return h, parts[3], nil
+}
+
+func extractBackupFile(r io.Reader, name, out string) error {
+	h, expected, err := blobHasher(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(path.Dir(out), 0755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if h != nil {
+		r = io.TeeReader(r, h)
+	}
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		return err
+	}
+
+	if h != nil && hex.EncodeToString(h.Sum(nil)) != expected {
+		return fmt.Errorf("%w: %s: content doesn't match the hash", ErrInvalidBackup, name)
+	}
+
+	return f.Close()
+}
+
+func extractBackup(ctx context.Context, p, dir string, withDatabase bool) (int, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+
+	tr := tar.NewReader(zr)
+
+	_, err = readBackupManifest(tr)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	hasDatabase := false
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+
+		header, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return count, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := header.Name
+		if name == backupDatabaseName {
+			hasDatabase = true
+
+			// NOTE(patrik): Every backup contains the full database so
+			// only the one from the last backup is used
+			if !withDatabase {
+				continue
+			}
+		} else if !validBackupName(name) {
+			return count, fmt.Errorf("%w: unexpected entry '%s'", ErrInvalidBackup, name)
+		}
+
+		err = extractBackupFile(tr, name, path.Join(dir, name))
+		if err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	if !hasDatabase {
+		return count, fmt.Errorf("%w: missing database", ErrInvalidBackup)
+	}
+
+	return count, nil
+}
+
+// ValidateBackupChain checks that the first backup is a full backup and
+// that each of the following is an incremental backup of the one before
+func ValidateBackupChain(backups []string) ([]BackupManifest, error) {
+	if len(backups) == 0 {
+		return nil, ErrNoBackups
+	}
+
+	manifests := make([]BackupManifest, len(backups))
+	for i, p := range backups {
+		manifest, err := ReadBackupManifest(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		if i == 0 && manifest.Incremental() {
+			return nil, fmt.Errorf("%w: %s: the first backup needs to be a full backup", ErrInvalidBackup, p)
+		}
+
+		if i > 0 && manifest.BaseId != manifests[i-1].Id {
+			return nil, fmt.Errorf("%w: %s: not based on %s", ErrInvalidBackup, p, backups[i-1])
+		}
+
+		manifests[i] = manifest
+	}
+
+	return manifests, nil
+}
+
+// RestoreBackup rehydrates the backups into an empty data directory, the
+// first backup needs to be a full backup followed by the incremental
+// backups in the order they were created. Returns the number of restored
+// files
+//
+// NOTE(patrik): Files removed between the backups are restored as well,
+// running gc afterwards removes them
+func RestoreBackup(ctx context.Context, dir string, backups []string) (int, error) {
+	_, err := ValidateBackupChain(backups)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	if len(entries) > 0 {
+		return 0, ErrRestoreDirNotEmpty
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := restoreBackup(ctx, dir, backups)
+	if err != nil {
+		// NOTE(patrik): The directory was empty before the restore
+		removeErr := removeDirContents(dir)
+		return 0, errors.Join(err, removeErr)
+	}
+
+	return count, nil
+}
+
+func restoreBackup(ctx context.Context, dir string, backups []string) (int, error) {
+	total := 0
+	for i, p := range backups {
+		count, err := extractBackup(ctx, p, dir, i == len(backups)-1)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", p, err)
+		}
+
+		total += count
+	}
+
+	db, err := database.Open(types.WorkDir(dir).DatabaseFile())
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	err = db.CheckIntegrity(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func removeDirContents(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		errs = append(errs, os.RemoveAll(path.Join(dir, entry.Name())))
+	}
+
+	return errors.Join(errs...)
+}
+
+func backupJob(job *JobContext) error {
+	var payload BackupPayload
+	err := job.Payload(&payload)
+	if err != nil {
+		return err
+	}
+
+	app := job.App()
+	dir := app.WorkDir().BackupsDir()
+
+	var base *BackupManifest
+	if payload.Incremental {
+		latest, err := LatestBackup(dir)
+		switch {
+		case err == nil:
+			base = &latest
+			job.Log("Creating an incremental backup based on %s", latest.Id)
+		case errors.Is(err, ErrNoBackups):
+			job.Log("No previous backup, creating a full backup")
+		default:
+			return err
+		}
+	}
+
+	_, res, err := WriteBackup(job, app, dir, base)
+	if err != nil {
+		return err
+	}
+
+	job.Log("Wrote %d files (%d bytes) to '%s'", res.Files, res.Bytes, res.File)
+
+	return job.SetResult(res)
+}
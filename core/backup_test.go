@@ -0,0 +1,109 @@
+package core
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: "Backup",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := addTestImage(t, app, collectionId, grayImage(20, 20, func(x, y int) uint8 { return uint8(x * y) }), 0)
+
+	dir := app.WorkDir().BackupsDir()
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	full, _, err := WriteBackup(ctx, app, dir, nil)
+	if err != nil {
+		t.Fatalf("failed to write full backup: %v", err)
+	}
+
+	base, err := ReadBackupManifest(full)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := addTestImage(t, app, collectionId, grayImage(20, 20, func(x, y int) uint8 { return uint8(x + y) }), 1)
+
+	// NOTE(patrik): Written right after the full backup so the names
+	// needs to be unique even inside the same second
+	incremental, res, err := WriteBackup(ctx, app, dir, &base)
+	if err != nil {
+		t.Fatalf("failed to write incremental backup: %v", err)
+	}
+
+	if incremental == full {
+		t.Fatal("backups got the same name")
+	}
+
+	// NOTE(patrik): The database and the new blob
+	if res.Files < 2 {
+		t.Fatalf("expected the incremental backup to contain the new files, got %d", res.Files)
+	}
+
+	out := path.Join(t.TempDir(), "restore")
+
+	_, err = RestoreBackup(ctx, out, []string{incremental})
+	if err == nil {
+		t.Fatal("expected restoring only an incremental backup to fail")
+	}
+
+	_, err = RestoreBackup(ctx, out, []string{full, incremental})
+	if err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+
+	db, err := database.Open(types.WorkDir(out).DatabaseFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	images, err := db.GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != 2 || images[0].Id != first.Id || images[1].Id != second.Id {
+		t.Fatalf("unexpected restored images %+v", images)
+	}
+
+	for _, img := range images {
+		restored, err := os.ReadFile(path.Join(types.WorkDir(out).BlobsDir(), ImageKey(img)))
+		if err != nil {
+			t.Fatalf("blob of %s wasn't restored: %v", img.Id, err)
+		}
+
+		r, err := app.Storage().Get(ctx, ImageKey(img))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		original, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(restored) != string(original) {
+			t.Fatalf("blob of %s doesn't match", img.Id)
+		}
+	}
+}
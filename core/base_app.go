@@ -1,21 +1,28 @@
 package core
 
 import (
+	"context"
 	"os"
+	"time"
 
 	"github.com/nanoteck137/pyrin/trail"
 	"github.com/nanoteck137/storebook"
 	"github.com/nanoteck137/storebook/config"
 	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/storage"
 	"github.com/nanoteck137/storebook/types"
 )
 
 var _ App = (*BaseApp)(nil)
 
 type BaseApp struct {
-	logger          *trail.Logger
-	db              *database.Database
-	config          *config.Config
+	logger    *trail.Logger
+	db        *database.Database
+	config    *config.Config
+	jobs      *JobRunner
+	broker    *Broker
+	scheduler *Scheduler
+	storage   storage.Storage
 }
 
 func (app *BaseApp) Logger() *trail.Logger {
@@ -30,6 +37,22 @@ func (app *BaseApp) Config() *config.Config {
 	return app.config
 }
 
+func (app *BaseApp) Broker() *Broker {
+	return app.broker
+}
+
+func (app *BaseApp) Scheduler() *Scheduler {
+	return app.scheduler
+}
+
+func (app *BaseApp) Storage() storage.Storage {
+	return app.storage
+}
+
+func (app *BaseApp) Jobs() *JobRunner {
+	return app.jobs
+}
+
 func (app *BaseApp) WorkDir() types.WorkDir {
 	return app.config.WorkDir()
 }
@@ -41,6 +64,10 @@ func (app *BaseApp) Bootstrap() error {
 
 	dirs := []string{
 		workDir.CollectionsDir(),
+		workDir.UploadsDir(),
+		workDir.ExportsDir(),
+		workDir.BlobsDir(),
+		workDir.BackupsDir(),
 	}
 
 	for _, dir := range dirs {
@@ -50,6 +77,11 @@ func (app *BaseApp) Bootstrap() error {
 		}
 	}
 
+	app.storage, err = newStorage(app.config)
+	if err != nil {
+		return err
+	}
+
 	app.db, err = database.Open(workDir.DatabaseFile())
 	if err != nil {
 		return err
@@ -60,14 +92,84 @@ func (app *BaseApp) Bootstrap() error {
 		if err != nil {
 			return err
 		}
+
+		err = migrateLegacyImages(context.Background(), app)
+		if err != nil {
+			return err
+		}
 	}
 
+	app.broker.Start()
+
 	return nil
 }
 
+func newStorage(conf *config.Config) (storage.Storage, error) {
+	switch conf.Storage {
+	case "s3":
+		return storage.NewS3(storage.S3Options{
+			Endpoint:      conf.S3.Endpoint,
+			Region:        conf.S3.Region,
+			Bucket:        conf.S3.Bucket,
+			AccessKey:     conf.S3.AccessKey,
+			SecretKey:     conf.S3.SecretKey,
+			PathStyle:     conf.S3.PathStyle,
+			PresignExpiry: time.Duration(conf.S3.PresignExpiry) * time.Second,
+		})
+	default:
+		return storage.NewLocal(conf.WorkDir().BlobsDir()), nil
+	}
+}
+
 func NewBaseApp(config *config.Config) *BaseApp {
-	return &BaseApp{
+	app := &BaseApp{
 		logger: storebook.DefaultLogger(),
 		config: config,
 	}
+
+	app.broker = NewBroker(app)
+
+	app.jobs = NewJobRunner(app)
+	app.jobs.Register(JobTypeImportArchive, importArchiveJob)
+	app.jobs.Register(JobTypeGenerateThumbnails, generateThumbnailsJob)
+	app.jobs.Register(JobTypeExportCollection, exportCollectionJob)
+	app.jobs.Register(JobTypeVerifyImages, verifyImagesJob)
+	app.jobs.Register(JobTypeRehashImages, rehashImagesJob)
+	app.jobs.Register(JobTypeAnalyzeImages, analyzeImagesJob)
+	app.jobs.Register(JobTypeSplitSpreads, splitSpreadsJob)
+	app.jobs.Register(JobTypeBackup, backupJob)
+
+	app.scheduler = NewScheduler(app)
+	app.scheduler.Register(Task{
+		Name:            "trash-purge",
+		Description:     "Purge collections that have been in the trash longer than the retention period",
+		DefaultSchedule: "@hourly",
+	}, PurgeTrash)
+	app.scheduler.Register(Task{
+		Name:            "release-check",
+		Description:     "Send notifications for newly released parts of tracked collections",
+		DefaultSchedule: "@hourly",
+	}, CheckReleases)
+	app.scheduler.Register(Task{
+		Name:            "blob-cleanup",
+		Description:     "Remove blobs that are no longer referenced by any image",
+		DefaultSchedule: "@hourly",
+	}, blobCleanupTask)
+	app.scheduler.Register(Task{
+		Name:            "gc",
+		Description:     "Remove files without a database row and images without a file",
+		DefaultSchedule: "@daily",
+	}, gcTask)
+	app.scheduler.Register(Task{
+		Name:            "verify",
+		Description:     "Check that the stored images still match their hashes",
+		DefaultSchedule: "@weekly",
+	}, verifyTask)
+	app.scheduler.Register(Task{
+		Name:            "watch-import",
+		Description:     "Import the archives added to the watch directories",
+		DefaultSchedule: "@every 1m",
+	}, newWatchImporter().run)
+
+	return app
 }
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/storage"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+// NOTE(patrik): Unreferenced blobs touched within this period are kept
+// around so that a blob stored by an import isn't removed before the
+// image row referencing it has been created
+const blobGracePeriod = 10 * time.Minute
+
+// NOTE(patrik): Blobs are sharded by the first two characters of the hash
+// to keep the directories small
+func BlobKey(alg types.HashAlgorithm, hash string) string {
+	return path.Join(string(alg), hash[:2], hash)
+}
+
+// ImageKey returns the storage key of the blob holding the image data
+func ImageKey(img database.Image) string {
+	return BlobKey(img.HashAlgorithm, img.Hash)
+}
+
+// OpenImage opens the blob holding the image data
+func OpenImage(ctx context.Context, app App, img database.Image) (io.ReadSeekCloser, storage.Object, error) {
+	return storage.Open(ctx, app.Storage(), ImageKey(img))
+}
+
+func putBlob(ctx context.Context, app App, key string, r io.Reader, size int64) error {
+	exists, err := storage.Exists(ctx, app.Storage(), key)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	return app.Storage().Put(ctx, key, r, size)
+}
+
+// StoreBlob writes the data to the blob store if it isn't already stored
+// and returns the hash, the caller is responsible for creating the image
+// row that references the blob
+func StoreBlob(ctx context.Context, app App, data []byte, alg types.HashAlgorithm) (string, error) {
+	h, err := utils.NewHasher(alg)
+	if err != nil {
+		return "", err
+	}
+
+	h.Write(data)
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	err = app.DB().CreateOrTouchBlob(ctx, database.CreateBlobParams{
+		Hash:          hash,
+		HashAlgorithm: alg,
+		Size:          int64(len(data)),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = putBlob(ctx, app, BlobKey(alg, hash), bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// copyBlob stores a copy of an existing blob under a new hash
+func copyBlob(ctx context.Context, app App, src database.Image, alg types.HashAlgorithm, hash string) error {
+	r, obj, err := OpenImage(ctx, app, src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	err = app.DB().CreateOrTouchBlob(ctx, database.CreateBlobParams{
+		Hash:          hash,
+		HashAlgorithm: alg,
+		Size:          obj.Size,
+	})
+	if err != nil {
+		return err
+	}
+
+	return putBlob(ctx, app, BlobKey(alg, hash), r, obj.Size)
+}
+
+// RemoveBlobIfUnreferenced removes the blob and its file when no image
+// references it anymore
+func RemoveBlobIfUnreferenced(ctx context.Context, app App, alg types.HashAlgorithm, hash string) error {
+	blob, err := app.DB().GetBlob(ctx, alg, hash)
+	if err != nil {
+		if errors.Is(err, database.ErrItemNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if blob.RefCount > 0 || time.Since(time.UnixMilli(blob.Updated)) < blobGracePeriod {
+		return nil
+	}
+
+	return removeBlob(ctx, app, blob)
+}
+
+func removeBlob(ctx context.Context, app App, blob database.Blob) error {
+	removed, err := app.DB().RemoveUnreferencedBlob(ctx, blob.HashAlgorithm, blob.Hash)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return nil
+	}
+
+	return app.Storage().Delete(ctx, BlobKey(blob.HashAlgorithm, blob.Hash))
+}
+
+// RemoveUnreferencedBlobs removes all the blobs that has been without
+// references for longer than the grace period
+func RemoveUnreferencedBlobs(ctx context.Context, app App) (int, error) {
+	before := time.Now().Add(-blobGracePeriod).UnixMilli()
+
+	blobs, err := app.DB().GetUnreferencedBlobs(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, blob := range blobs {
+		err := removeBlob(ctx, app, blob)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(blobs), nil
+}
+
+// migrateLegacyImages moves the image files stored inside the collection
+// directories into the blob store
+func migrateLegacyImages(ctx context.Context, app App) error {
+	collectionsDir := app.WorkDir().CollectionsDir()
+
+	entries, err := os.ReadDir(collectionsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		dir := app.WorkDir().CollectionDirById(entry.Name())
+
+		files, err := os.ReadDir(dir.Images())
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		images, err := app.DB().GetAllImagesByCollectionId(ctx, entry.Name())
+		if err != nil {
+			return err
+		}
+
+		byFilename := make(map[string]database.Image, len(images))
+		for _, img := range images {
+			byFilename[img.Filename] = img
+		}
+
+		moved := 0
+		for _, file := range files {
+			img, exists := byFilename[file.Name()]
+			if !exists {
+				continue
+			}
+
+			err := moveLegacyImage(ctx, app, path.Join(dir.Images(), file.Name()), img)
+			if err != nil {
+				return err
+			}
+
+			moved++
+		}
+
+		app.Logger().Info("Moved images into the blob store", "collectionId", entry.Name(), "count", moved)
+
+		// NOTE(patrik): Only succeeds when every file was moved, the files
+		// left behind are reported by the garbage collector
+		os.Remove(dir.Images())
+	}
+
+	return nil
+}
+
+func blobCleanupTask(ctx context.Context, app App) error {
+	count, err := RemoveUnreferencedBlobs(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		app.Logger().Info("Removed unreferenced blobs", "count", count)
+	}
+
+	return nil
+}
+
+func moveLegacyImage(ctx context.Context, app App, p string, img database.Image) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	err = app.DB().CreateOrTouchBlob(ctx, database.CreateBlobParams{
+		Hash:          img.Hash,
+		HashAlgorithm: img.HashAlgorithm,
+		Size:          info.Size(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = putBlob(ctx, app, ImageKey(img), f, info.Size())
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(p)
+}
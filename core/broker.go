@@ -0,0 +1,99 @@
+package core
+
+import (
+	"sync/atomic"
+)
+
+const (
+	brokerNotifierSize = 128
+	brokerClientSize   = 64
+)
+
+type EventData interface {
+	GetEventType() string
+}
+
+// NOTE(patrik): Based on: https://gist.github.com/Ananto30/8af841f250e89c07e122e2a838698246
+type Broker struct {
+	app App
+
+	notifier chan EventData
+
+	newClients     chan chan EventData
+	closingClients chan chan EventData
+	clients        map[chan EventData]bool
+
+	started atomic.Bool
+}
+
+func NewBroker(app App) *Broker {
+	return &Broker{
+		app:            app,
+		notifier:       make(chan EventData, brokerNotifierSize),
+		newClients:     make(chan chan EventData),
+		closingClients: make(chan chan EventData),
+		clients:        make(map[chan EventData]bool),
+	}
+}
+
+// Start starts the listening and broadcasting of events
+func (broker *Broker) Start() {
+	if broker.started.Swap(true) {
+		return
+	}
+
+	go broker.listen()
+}
+
+func (broker *Broker) listen() {
+	logger := broker.app.Logger()
+
+	for {
+		select {
+		case s := <-broker.newClients:
+			broker.clients[s] = true
+			logger.Debug("Client added", "numClients", len(broker.clients))
+		case s := <-broker.closingClients:
+			if broker.clients[s] {
+				delete(broker.clients, s)
+				close(s)
+			}
+			logger.Debug("Removed client", "numClients", len(broker.clients))
+		case event := <-broker.notifier:
+			for clientMessageChan := range broker.clients {
+				select {
+				case clientMessageChan <- event:
+				default:
+					// NOTE(patrik): The client is not keeping up, drop it
+					// instead of blocking the other clients, the client
+					// can reconnect and fetch the current state again
+					delete(broker.clients, clientMessageChan)
+					close(clientMessageChan)
+					logger.Warn("Dropped slow client", "numClients", len(broker.clients))
+				}
+			}
+		}
+	}
+}
+
+// Subscribe registers a new client, the returned channel is closed when
+// the client is unsubscribed or when it's too slow to receive events
+func (broker *Broker) Subscribe() chan EventData {
+	ch := make(chan EventData, brokerClientSize)
+	broker.newClients <- ch
+	return ch
+}
+
+func (broker *Broker) Unsubscribe(ch chan EventData) {
+	broker.closingClients <- ch
+}
+
+// EmitEvent sends the event to all the subscribed clients, events emitted
+// before the broker is started are dropped
+func (broker *Broker) EmitEvent(event EventData) {
+	if !broker.started.Load() {
+		return
+	}
+
+	broker.notifier <- event
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+// CreateCollection creates an empty collection together with its
+// directory inside the work dir, the id inside the params is ignored
+func CreateCollection(ctx context.Context, app App, params database.CreateCollectionParams) (string, error) {
+	id := utils.CreateCollectionId()
+	params.Id = id
+
+	collectionDir := app.WorkDir().CollectionDirById(id)
+	err := collectionDir.Create()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = app.DB().CreateCollection(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionCreated,
+		CollectionId: id,
+	})
+
+	return id, nil
+}
+
+func removeImageFromTx(ctx context.Context, tx database.Tx, img database.Image) error {
+	err := tx.RemoveImage(ctx, img.CollectionId, img.Id)
+	if err != nil {
+		return err
+	}
+
+	next, err := tx.GetNextImagePosition(ctx, img.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	return tx.ShiftImagePositions(ctx, img.CollectionId, img.Position+1, next, -1)
+}
+
+// RemoveCollectionImage removes the image and closes the gap in the
+// positions. Removing a page created from a spread undoes the split, the
+// other pages are removed as well and the spread is shown again. Removing
+// a spread removes the pages created from it
+func RemoveCollectionImage(ctx context.Context, app App, collectionId, imageId string) error {
+	tx, err := app.DB().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// NOTE(patrik): Fetch the image inside the transaction so the
+	// positions are up to date
+	dbImage, err := tx.GetImageById(ctx, collectionId, imageId)
+	if err != nil {
+		return err
+	}
+
+	var removed []database.Image
+	if dbImage.SourceId.Valid {
+		pages, err := undoSplitTx(ctx, tx, collectionId, dbImage.SourceId.String)
+		if err != nil {
+			return err
+		}
+
+		removed = pages
+	} else {
+		if dbImage.Split.Valid {
+			pages, err := undoSplitTx(ctx, tx, collectionId, dbImage.Id)
+			if err != nil {
+				return err
+			}
+
+			removed = pages
+
+			// NOTE(patrik): The pages can have been moved in front of
+			// the spread so the position needs to be refetched
+			dbImage, err = tx.GetImageById(ctx, collectionId, dbImage.Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := removeImageFromTx(ctx, tx, dbImage)
+		if err != nil {
+			return err
+		}
+
+		removed = append(removed, dbImage)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionUpdated,
+		CollectionId: collectionId,
+	})
+
+	return removeImageBlobs(ctx, app, removed)
+}
+
+func removeImageBlobs(ctx context.Context, app App, images []database.Image) error {
+	for _, img := range images {
+		err := RemoveBlobIfUnreferenced(ctx, app, img.HashAlgorithm, img.Hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReplaceCollectionImage swaps the content of the image while keeping its
+// position. A spread that has been split is restored before the swap so
+// the new content isn't hidden behind the old pages
+func ReplaceCollectionImage(ctx context.Context, app App, collectionId, imageId string, data []byte, ext string) error {
+	alg := app.Config().HashAlgorithm
+	hash, err := StoreBlob(ctx, app, data, alg)
+	if err != nil {
+		return err
+	}
+
+	analysis, err := AnalyzeImage(data)
+	if err != nil {
+		app.Logger().Warn("Failed to analyze image", "imageId", imageId, "err", err)
+	}
+
+	tx, err := app.DB().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	dbImage, err := tx.GetImageById(ctx, collectionId, imageId)
+	if err != nil {
+		return err
+	}
+
+	var removed []database.Image
+	if dbImage.Split.Valid {
+		removed, err = undoSplitTx(ctx, tx, collectionId, dbImage.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	filename := hash + ext
+
+	changes := analysis.Changes()
+	changes.Hash = database.Change[string]{
+		Value:   hash,
+		Changed: hash != dbImage.Hash,
+	}
+	changes.HashAlgorithm = database.Change[types.HashAlgorithm]{
+		Value:   alg,
+		Changed: alg != dbImage.HashAlgorithm,
+	}
+	changes.Filename = database.Change[string]{
+		Value:   filename,
+		Changed: filename != dbImage.Filename,
+	}
+	changes.Verified = database.Change[sql.NullInt64]{
+		Value:   sql.NullInt64{},
+		Changed: hash != dbImage.Hash,
+	}
+
+	err = tx.UpdateImage(ctx, collectionId, dbImage.Id, changes)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionUpdated,
+		CollectionId: collectionId,
+	})
+
+	err = removeImageBlobs(ctx, app, append(removed, dbImage))
+	if err != nil {
+		return err
+	}
+
+	// NOTE(patrik): The thumbnails are named after the hash so the new
+	// content needs its own thumbnails
+	_, err = app.Jobs().Enqueue(ctx, JobTypeGenerateThumbnails, GenerateThumbnailsPayload{
+		CollectionId: collectionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
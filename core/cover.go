@@ -0,0 +1,131 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"path"
+	"strings"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+var ErrInvalidCoverImage = errors.New("invalid cover image")
+
+// CoverKey returns the storage key of the custom cover of the collection
+func CoverKey(collection database.Collection) string {
+	return BlobKey(types.HashAlgorithm(collection.CoverHashAlgorithm.String), collection.CoverHash.String)
+}
+
+func clearCustomCover(changes *database.CollectionChanges) {
+	changes.CoverHash = database.Change[sql.NullString]{Changed: true}
+	changes.CoverHashAlgorithm = database.Change[sql.NullString]{Changed: true}
+	changes.CoverFilename = database.Change[sql.NullString]{Changed: true}
+}
+
+func updateCover(ctx context.Context, app App, collection database.Collection, changes database.CollectionChanges) error {
+	err := app.DB().UpdateCollection(ctx, collection.Id, changes)
+	if err != nil {
+		return err
+	}
+
+	if collection.CoverHash.Valid {
+		alg := types.HashAlgorithm(collection.CoverHashAlgorithm.String)
+
+		err := RemoveBlobIfUnreferenced(ctx, app, alg, collection.CoverHash.String)
+		if err != nil {
+			return err
+		}
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionUpdated,
+		CollectionId: collection.Id,
+	})
+
+	return nil
+}
+
+// SetCustomCover stores the data as the cover of the collection and
+// replaces the picked image, the thumbnails are generated right away
+// because the thumbnail job only handles the images of the collection
+func SetCustomCover(ctx context.Context, app App, collection database.Collection, data []byte, filename string) error {
+	ext := strings.ToLower(path.Ext(filename))
+	if !utils.IsImageExt(ext) {
+		return ErrInvalidCoverImage
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ErrInvalidCoverImage
+	}
+
+	alg := app.Config().HashAlgorithm
+	hash, err := StoreBlob(ctx, app, data, alg)
+	if err != nil {
+		return err
+	}
+
+	collectionDir := app.WorkDir().CollectionDirById(collection.Id)
+	err = collectionDir.Create()
+	if err != nil {
+		return err
+	}
+
+	for _, size := range ThumbnailSizes {
+		out := path.Join(collectionDir.Thumbnails(), ThumbnailFilename(hash, size.Name))
+
+		err := writeThumbnail(src, size.Width, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	return updateCover(ctx, app, collection, database.CollectionChanges{
+		CoverImageId: database.Change[sql.NullString]{
+			Changed: true,
+		},
+		CoverHash: database.Change[sql.NullString]{
+			Value:   sql.NullString{String: hash, Valid: true},
+			Changed: true,
+		},
+		CoverHashAlgorithm: database.Change[sql.NullString]{
+			Value:   sql.NullString{String: string(alg), Valid: true},
+			Changed: true,
+		},
+		CoverFilename: database.Change[sql.NullString]{
+			Value:   sql.NullString{String: hash + ext, Valid: true},
+			Changed: true,
+		},
+	})
+}
+
+// SetCoverImage uses one of the images of the collection as the cover,
+// replaces the custom cover if one has been uploaded
+func SetCoverImage(ctx context.Context, app App, collection database.Collection, imageId string) error {
+	changes := database.CollectionChanges{
+		CoverImageId: database.Change[sql.NullString]{
+			Value:   sql.NullString{String: imageId, Valid: true},
+			Changed: true,
+		},
+	}
+	clearCustomCover(&changes)
+
+	return updateCover(ctx, app, collection, changes)
+}
+
+// ResetCover makes the collection use the first page as the cover
+func ResetCover(ctx context.Context, app App, collection database.Collection) error {
+	changes := database.CollectionChanges{
+		CoverImageId: database.Change[sql.NullString]{
+			Changed: true,
+		},
+	}
+	clearCustomCover(&changes)
+
+	return updateCover(ctx, app, collection, changes)
+}
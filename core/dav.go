@@ -0,0 +1,520 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/storage"
+	"github.com/nanoteck137/storebook/utils"
+	"golang.org/x/net/webdav"
+)
+
+const davCollectionsDir = "collections"
+
+var davNameReplacer = strings.NewReplacer("/", "_", `\`, "_")
+
+// DavFileSystem is a read-only view of the library generated from the
+// database, laid out as collections/<title>/<page>.<ext>
+type DavFileSystem struct {
+	app App
+}
+
+func NewDavFileSystem(app App) *DavFileSystem {
+	return &DavFileSystem{app: app}
+}
+
+var _ webdav.FileSystem = (*DavFileSystem)(nil)
+
+func (d *DavFileSystem) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	return os.ErrPermission
+}
+
+func (d *DavFileSystem) RemoveAll(ctx context.Context, name string) error {
+	return os.ErrPermission
+}
+
+func (d *DavFileSystem) Rename(ctx context.Context, oldName, newName string) error {
+	return os.ErrPermission
+}
+
+// NOTE(patrik): PROPFIND stats every entry of a directory so the
+// directories are resolved without loading the pages
+func (d *DavFileSystem) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	parts := davSplitPath(name)
+	if len(parts) == 2 && parts[0] == davCollectionsDir {
+		collection, err := d.collection(ctx, parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return davCollectionInfo(parts[1], collection), nil
+	}
+
+	f, err := d.OpenFile(ctx, name, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return f.Stat()
+}
+
+func (d *DavFileSystem) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		return nil, os.ErrPermission
+	}
+
+	parts := davSplitPath(name)
+	if len(parts) > 0 && parts[0] != davCollectionsDir {
+		return nil, os.ErrNotExist
+	}
+
+	switch len(parts) {
+	case 0, 1:
+		collections, err := d.collections(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(parts) == 0 {
+			info := davCollectionsInfo(collections)
+			return newDavDir(davFileInfo{name: "/", dir: true, modTime: info.modTime}, []fs.FileInfo{info}), nil
+		}
+
+		entries := make([]fs.FileInfo, 0, len(collections))
+		for name, collection := range collections {
+			entries = append(entries, davCollectionInfo(name, collection))
+		}
+
+		return newDavDir(davCollectionsInfo(collections), entries), nil
+	}
+
+	collection, err := d.collection(ctx, parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := d.pages(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(parts) {
+	case 2:
+		entries := make([]fs.FileInfo, 0, len(pages))
+		for _, page := range pages {
+			entries = append(entries, page)
+		}
+
+		return newDavDir(davCollectionInfo(parts[1], collection), entries), nil
+	case 3:
+		for _, page := range pages {
+			if page.name == parts[2] {
+				return &davImageFile{ctx: ctx, app: d.app, info: page}, nil
+			}
+		}
+	}
+
+	return nil, os.ErrNotExist
+}
+
+// NOTE(patrik): The webdav handler calls Stat and OpenFile for every entry
+// of a PROPFIND, the cache keeps the lookups for the length of a request
+type davCache struct {
+	collections map[string]database.Collection
+	resolved    map[string]database.Collection
+	pages       map[string][]davFileInfo
+}
+
+type davCacheKey struct{}
+
+// WithDavCache returns a context that caches the collections and the
+// pages looked up by the DavFileSystem, used once per request
+func WithDavCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, davCacheKey{}, &davCache{
+		resolved: make(map[string]database.Collection),
+		pages:    make(map[string][]davFileInfo),
+	})
+}
+
+func davCacheFromContext(ctx context.Context) *davCache {
+	cache, _ := ctx.Value(davCacheKey{}).(*davCache)
+	return cache
+}
+
+// NOTE(patrik): Titles are not unique so collections sharing a title gets
+// the id added to the directory name
+func (d *DavFileSystem) collections(ctx context.Context) (map[string]database.Collection, error) {
+	cache := davCacheFromContext(ctx)
+	if cache != nil && cache.collections != nil {
+		return cache.collections, nil
+	}
+
+	collections, err := d.app.DB().GetAllCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	count := make(map[string]int, len(collections))
+	for _, collection := range collections {
+		count[davCollectionName(collection)]++
+	}
+
+	res := make(map[string]database.Collection, len(collections))
+	for _, collection := range collections {
+		name := davCollectionName(collection)
+		if count[name] > 1 {
+			name = fmt.Sprintf("%s (%s)", name, collection.Id)
+		}
+
+		res[name] = collection
+	}
+
+	if cache != nil {
+		cache.collections = res
+	}
+
+	return res, nil
+}
+
+// collection resolves a single directory name without loading every
+// collection, unless the listing has already been loaded for the request
+func (d *DavFileSystem) collection(ctx context.Context, name string) (database.Collection, error) {
+	cache := davCacheFromContext(ctx)
+	if cache != nil {
+		if cache.collections != nil {
+			collection, exists := cache.collections[name]
+			if !exists {
+				return database.Collection{}, os.ErrNotExist
+			}
+
+			return collection, nil
+		}
+
+		collection, exists := cache.resolved[name]
+		if exists {
+			return collection, nil
+		}
+	}
+
+	collection, err := d.lookupCollection(ctx, name)
+	if err != nil {
+		return database.Collection{}, err
+	}
+
+	if cache != nil {
+		cache.resolved[name] = collection
+	}
+
+	return collection, nil
+}
+
+func (d *DavFileSystem) lookupCollection(ctx context.Context, name string) (database.Collection, error) {
+	collections, err := d.collectionsNamed(ctx, name)
+	if err != nil {
+		return database.Collection{}, err
+	}
+
+	if len(collections) == 1 {
+		return collections[0], nil
+	}
+
+	// NOTE(patrik): "<title> (<id>)" is only used when the title is
+	// shared with other collections
+	base, id, found := strings.Cut(name, " (")
+	for found {
+		if strings.HasSuffix(id, ")") && !strings.Contains(id, " (") {
+			id = strings.TrimSuffix(id, ")")
+			break
+		}
+
+		var rest string
+		rest, id, found = strings.Cut(id, " (")
+		base = base + " (" + rest
+	}
+
+	if !found {
+		return database.Collection{}, os.ErrNotExist
+	}
+
+	collections, err = d.collectionsNamed(ctx, base)
+	if err != nil {
+		return database.Collection{}, err
+	}
+
+	if len(collections) > 1 {
+		for _, collection := range collections {
+			if collection.Id == id {
+				return collection, nil
+			}
+		}
+	}
+
+	return database.Collection{}, os.ErrNotExist
+}
+
+// NOTE(patrik): "_" is left as a wildcard because "/" and "\" inside the
+// titles are replaced with "_"
+var davLikeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`)
+
+// collectionsNamed returns the collections that has the name before the id
+// is added to make it unique
+func (d *DavFileSystem) collectionsNamed(ctx context.Context, name string) ([]database.Collection, error) {
+	candidates, err := d.app.DB().GetCollectionsByTitleLike(ctx, davLikeEscaper.Replace(name))
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE(patrik): Collections without a usable title are named after
+	// the id
+	collection, err := d.app.DB().GetCollectionById(ctx, name)
+	if err == nil {
+		candidates = append(candidates, collection)
+	} else if !errors.Is(err, database.ErrItemNotFound) {
+		return nil, err
+	}
+
+	var res []database.Collection
+	seen := make(map[string]bool)
+	for _, collection := range candidates {
+		if seen[collection.Id] || davCollectionName(collection) != name {
+			continue
+		}
+
+		seen[collection.Id] = true
+		res = append(res, collection)
+	}
+
+	return res, nil
+}
+
+func (d *DavFileSystem) pages(ctx context.Context, collection database.Collection) ([]davFileInfo, error) {
+	cache := davCacheFromContext(ctx)
+	if cache != nil {
+		pages, exists := cache.pages[collection.Id]
+		if exists {
+			return pages, nil
+		}
+	}
+
+	images, err := d.app.DB().GetAllImagesByCollectionId(ctx, collection.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	images = ReadingPages(images)
+
+	width := max(3, len(strconv.Itoa(len(images))))
+
+	res := make([]davFileInfo, 0, len(images))
+	for i, img := range images {
+		size := img.Size.Int64
+		if !img.Size.Valid {
+			obj, err := d.app.Storage().Stat(ctx, ImageKey(img))
+			if err != nil {
+				return nil, err
+			}
+
+			size = obj.Size
+		}
+
+		res = append(res, davFileInfo{
+			name:    fmt.Sprintf("%0*d%s", width, i+1, strings.ToLower(path.Ext(img.Filename))),
+			size:    size,
+			modTime: time.UnixMilli(img.Updated),
+			image:   &img,
+		})
+	}
+
+	if cache != nil {
+		cache.pages[collection.Id] = res
+	}
+
+	return res, nil
+}
+
+func davSplitPath(name string) []string {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	if name == "" {
+		return nil
+	}
+
+	return strings.Split(name, "/")
+}
+
+func davCollectionName(collection database.Collection) string {
+	name := strings.TrimSpace(davNameReplacer.Replace(collection.Title))
+	if name == "" || name == "." || name == ".." {
+		return collection.Id
+	}
+
+	return name
+}
+
+func davCollectionsInfo(collections map[string]database.Collection) davFileInfo {
+	info := davFileInfo{name: davCollectionsDir, dir: true}
+	for _, collection := range collections {
+		t := time.UnixMilli(collection.Updated)
+		if t.After(info.modTime) {
+			info.modTime = t
+		}
+	}
+
+	return info
+}
+
+func davCollectionInfo(name string, collection database.Collection) davFileInfo {
+	return davFileInfo{
+		name:    name,
+		dir:     true,
+		modTime: time.UnixMilli(collection.Updated),
+	}
+}
+
+type davFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+
+	// NOTE(patrik): Only set for the pages
+	image *database.Image
+}
+
+func (i davFileInfo) Name() string       { return i.name }
+func (i davFileInfo) Size() int64        { return i.size }
+func (i davFileInfo) ModTime() time.Time { return i.modTime }
+func (i davFileInfo) IsDir() bool        { return i.dir }
+func (i davFileInfo) Sys() any           { return nil }
+
+func (i davFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+
+	return 0444
+}
+
+// NOTE(patrik): The content hash makes a better etag than the default
+// one made from the modification time and the size
+func (i davFileInfo) ETag(ctx context.Context) (string, error) {
+	if i.image == nil {
+		return "", webdav.ErrNotImplemented
+	}
+
+	return strconv.Quote(i.image.Hash), nil
+}
+
+func (i davFileInfo) ContentType(ctx context.Context) (string, error) {
+	if i.image == nil {
+		return "", webdav.ErrNotImplemented
+	}
+
+	if i.image.MimeType.Valid {
+		return i.image.MimeType.String, nil
+	}
+
+	contentType, err := utils.ImageExtToContentType(strings.ToLower(path.Ext(i.image.Filename)))
+	if err != nil {
+		return "", webdav.ErrNotImplemented
+	}
+
+	return contentType, nil
+}
+
+type davDir struct {
+	info    davFileInfo
+	entries []fs.FileInfo
+	pos     int
+}
+
+func newDavDir(info davFileInfo, entries []fs.FileInfo) *davDir {
+	return &davDir{info: info, entries: entries}
+}
+
+func (d *davDir) Close() error                                 { return nil }
+func (d *davDir) Read(p []byte) (int, error)                   { return 0, os.ErrInvalid }
+func (d *davDir) Seek(offset int64, whence int) (int64, error) { return 0, os.ErrInvalid }
+func (d *davDir) Write(p []byte) (int, error)                  { return 0, os.ErrPermission }
+func (d *davDir) Stat() (fs.FileInfo, error)                   { return d.info, nil }
+
+func (d *davDir) Readdir(count int) ([]fs.FileInfo, error) {
+	rest := d.entries[d.pos:]
+
+	if count <= 0 {
+		d.pos = len(d.entries)
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	n := min(count, len(rest))
+	d.pos += n
+
+	return rest[:n], nil
+}
+
+// NOTE(patrik): The blob is opened on the first read so that listing a
+// directory or a PROPFIND doesn't have to touch the storage
+type davImageFile struct {
+	ctx  context.Context
+	app  App
+	info davFileInfo
+
+	r io.ReadSeekCloser
+}
+
+func (f *davImageFile) open() error {
+	if f.r != nil {
+		return nil
+	}
+
+	r, _, err := storage.Open(f.ctx, f.app.Storage(), ImageKey(*f.info.image))
+	if err != nil {
+		return err
+	}
+
+	f.r = r
+	return nil
+}
+
+func (f *davImageFile) Read(p []byte) (int, error) {
+	err := f.open()
+	if err != nil {
+		return 0, err
+	}
+
+	return f.r.Read(p)
+}
+
+func (f *davImageFile) Seek(offset int64, whence int) (int64, error) {
+	err := f.open()
+	if err != nil {
+		return 0, err
+	}
+
+	return f.r.Seek(offset, whence)
+}
+
+func (f *davImageFile) Close() error {
+	if f.r == nil {
+		return nil
+	}
+
+	return f.r.Close()
+}
+
+func (f *davImageFile) Write(p []byte) (int, error)              { return 0, os.ErrPermission }
+func (f *davImageFile) Readdir(count int) ([]fs.FileInfo, error) { return nil, os.ErrInvalid }
+func (f *davImageFile) Stat() (fs.FileInfo, error)               { return f.info, nil }
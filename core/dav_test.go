@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/nanoteck137/storebook/database"
+)
+
+func TestDavCollectionLookup(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	create := func(title string) string {
+		t.Helper()
+
+		id, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+			Title: title,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return id
+	}
+
+	dup1 := create("Dup")
+	dup2 := create("Dup")
+	slash := create("A/B")
+	underscore := create("A_B")
+	percent := create("100% (x)")
+	single := create("Single")
+
+	fs := NewDavFileSystem(app)
+
+	listing, err := fs.collections(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listing) != 6 {
+		t.Fatalf("expected 6 collections in the listing, got %d", len(listing))
+	}
+
+	// NOTE(patrik): Every name in the listing should resolve to the same
+	// collection without loading the listing
+	for name, expected := range listing {
+		collection, err := fs.collection(ctx, name)
+		if err != nil {
+			t.Fatalf("%q: %v", name, err)
+		}
+
+		if collection.Id != expected.Id {
+			t.Errorf("%q: resolved %s, expected %s", name, collection.Id, expected.Id)
+		}
+	}
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{"Dup (" + dup1 + ")", dup1},
+		{"Dup (" + dup2 + ")", dup2},
+		{"A_B (" + slash + ")", slash},
+		{"A_B (" + underscore + ")", underscore},
+		{"100% (x)", percent},
+		{"Single", single},
+		{"Dup", ""},
+		{"Dup (missing)", ""},
+		{"Single (" + single + ")", ""},
+		{"A_B", ""},
+		{"100_ (x)", ""},
+		{"Missing", ""},
+	}
+
+	for _, test := range tests {
+		collection, err := fs.collection(ctx, test.name)
+		if test.id == "" {
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("%q: expected os.ErrNotExist, got %v", test.name, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: %v", test.name, err)
+			continue
+		}
+
+		if collection.Id != test.id {
+			t.Errorf("%q: resolved %s, expected %s", test.name, collection.Id, test.id)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package core
+
+import "github.com/nanoteck137/storebook/types"
+
+const (
+	EventCollectionCreated string = "collection-created"
+	EventCollectionUpdated string = "collection-updated"
+	EventCollectionDeleted string = "collection-deleted"
+	EventImportProgress    string = "import-progress"
+	EventJobUpdated        string = "job-updated"
+	EventNotification      string = "notification"
+)
+
+type CollectionEvent struct {
+	Type         string `json:"-"`
+	CollectionId string `json:"collectionId"`
+}
+
+func (e CollectionEvent) GetEventType() string {
+	return e.Type
+}
+
+type ImportProgressEvent struct {
+	JobId        string `json:"jobId"`
+	CollectionId string `json:"collectionId"`
+	Current      int    `json:"current"`
+	Total        int    `json:"total"`
+}
+
+func (e ImportProgressEvent) GetEventType() string {
+	return EventImportProgress
+}
+
+type JobEvent struct {
+	JobId  string          `json:"jobId"`
+	Type   string          `json:"type"`
+	Status types.JobStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+func (e JobEvent) GetEventType() string {
+	return EventJobUpdated
+}
+
+type NotificationEvent struct {
+	NotificationId string                 `json:"notificationId"`
+	Type           types.NotificationType `json:"type"`
+	Title          string                 `json:"title"`
+	Message        string                 `json:"message"`
+}
+
+func (e NotificationEvent) GetEventType() string {
+	return EventNotification
+}
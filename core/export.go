@@ -0,0 +1,116 @@
+package core
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+const JobTypeExportCollection = "export-collection"
+
+type ExportCollectionPayload struct {
+	CollectionId string `json:"collectionId"`
+}
+
+type ExportCollectionResult struct {
+	File string `json:"file"`
+}
+
+// ExportCollection writes the pages of the collection in reading order as a
+// CBZ archive to w
+func ExportCollection(ctx context.Context, app App, collectionId string, w io.Writer, progress ProgressFunc) error {
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		return err
+	}
+
+	images = ReadingPages(images)
+
+	zw := zip.NewWriter(w)
+
+	writeImage := func(i int, image database.Image) error {
+		f, _, err := OpenImage(ctx, app, image)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		// NOTE(patrik): The images are already compressed so store them as
+		// they are
+		name := fmt.Sprintf("%03d%s", i+1, path.Ext(image.Filename))
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:   name,
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(fw, f)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	for i, image := range images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := writeImage(i, image)
+		if err != nil {
+			return err
+		}
+
+		if progress != nil {
+			progress(i+1, len(images))
+		}
+	}
+
+	return zw.Close()
+}
+
+func exportCollectionJob(job *JobContext) error {
+	var payload ExportCollectionPayload
+	err := job.Payload(&payload)
+	if err != nil {
+		return err
+	}
+
+	app := job.App()
+
+	collection, err := app.DB().GetCollectionById(job, payload.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.cbz", utils.Slug(collection.Title), job.Id())
+	out := path.Join(app.WorkDir().ExportsDir(), name)
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = ExportCollection(job, app, collection.Id, f, job.SetProgress)
+	if err != nil {
+		f.Close()
+		os.Remove(out)
+		return err
+	}
+
+	job.Log("Exported '%s' to '%s'", collection.Title, name)
+
+	return job.SetResult(ExportCollectionResult{
+		File: name,
+	})
+}
@@ -0,0 +1,307 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+)
+
+// NOTE(patrik): Files newer than this is skipped so that we don't remove
+// files from uploads and imports that haven't created the row yet
+const gcGracePeriod = 1 * time.Hour
+
+type GCDanglingImage struct {
+	CollectionId string
+	ImageId      string
+	Filename     string
+}
+
+type GCReport struct {
+	DryRun bool
+
+	// NOTE(patrik): Paths relative to the data directory
+	OrphanFiles    []string
+	DanglingImages []GCDanglingImage
+
+	UnreferencedBlobs int
+
+	FreedBytes int64
+}
+
+func pathSize(p string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(p, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+func isRecent(p string, now time.Time) bool {
+	info, err := os.Stat(p)
+	if err != nil {
+		return false
+	}
+
+	return now.Sub(info.ModTime()) < gcGracePeriod
+}
+
+// CollectGarbage reconciles the collections directory and the storage
+// against the database, files without a row and rows without a file are
+// reported and removed unless dryRun is set
+func CollectGarbage(ctx context.Context, app App, dryRun bool) (GCReport, error) {
+	report := GCReport{
+		DryRun: dryRun,
+	}
+
+	db := app.DB()
+	now := time.Now()
+	workDir := app.WorkDir()
+	collectionsDir := workDir.CollectionsDir()
+
+	ids, err := db.GetAllCollectionIds(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	collections := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		collections[id] = true
+	}
+
+	allImages, err := db.GetAllImages(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	hashes := make(map[string]map[string]bool)
+	for _, img := range allImages {
+		if hashes[img.CollectionId] == nil {
+			hashes[img.CollectionId] = make(map[string]bool)
+		}
+
+		hashes[img.CollectionId][img.Hash] = true
+	}
+
+	// NOTE(patrik): Custom covers have thumbnails as well
+	covers, err := db.GetCollectionsWithCustomCover(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, collection := range covers {
+		if hashes[collection.Id] == nil {
+			hashes[collection.Id] = make(map[string]bool)
+		}
+
+		hashes[collection.Id][collection.CoverHash.String] = true
+	}
+
+	addOrphan := func(p string) error {
+		if isRecent(p, now) {
+			return nil
+		}
+
+		size, err := pathSize(p)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(workDir.String(), p)
+		if err != nil {
+			return err
+		}
+
+		report.OrphanFiles = append(report.OrphanFiles, rel)
+		report.FreedBytes += size
+
+		if !dryRun {
+			app.Logger().Info("Removing orphan file", "path", rel)
+
+			err := os.RemoveAll(p)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	entries, err := os.ReadDir(collectionsDir)
+	if err != nil {
+		return report, err
+	}
+
+	for _, entry := range entries {
+		p := path.Join(collectionsDir, entry.Name())
+
+		if !entry.IsDir() || !collections[entry.Name()] {
+			err := addOrphan(p)
+			if err != nil {
+				return report, err
+			}
+
+			continue
+		}
+
+		dir := app.WorkDir().CollectionDirById(entry.Name())
+
+		// NOTE(patrik): The images are moved into the blob store on
+		// startup so anything left here isn't referenced
+		_, err = os.Stat(dir.Images())
+		if err == nil {
+			err := addOrphan(dir.Images())
+			if err != nil {
+				return report, err
+			}
+		}
+
+		thumbnails, err := os.ReadDir(dir.Thumbnails())
+		if err != nil && !os.IsNotExist(err) {
+			return report, err
+		}
+
+		for _, thumbnail := range thumbnails {
+			hash, _, _ := strings.Cut(thumbnail.Name(), ".")
+			if hashes[entry.Name()][hash] {
+				continue
+			}
+
+			err := addOrphan(path.Join(dir.Thumbnails(), thumbnail.Name()))
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	objects, err := app.Storage().List(ctx, "")
+	if err != nil {
+		return report, err
+	}
+
+	stored := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		stored[obj.Key] = true
+	}
+
+	blobs, err := db.GetAllBlobs(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	knownBlobs := make(map[string]bool, len(blobs))
+	for _, blob := range blobs {
+		knownBlobs[BlobKey(blob.HashAlgorithm, blob.Hash)] = true
+
+		if blob.RefCount > 0 || now.Sub(time.UnixMilli(blob.Updated)) < blobGracePeriod {
+			continue
+		}
+
+		report.UnreferencedBlobs++
+		report.FreedBytes += blob.Size
+
+		if !dryRun {
+			err := removeBlob(ctx, app, blob)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	for _, obj := range objects {
+		if knownBlobs[obj.Key] || now.Sub(obj.ModTime) < gcGracePeriod {
+			continue
+		}
+
+		report.OrphanFiles = append(report.OrphanFiles, path.Join("blobs", obj.Key))
+		report.FreedBytes += obj.Size
+
+		if !dryRun {
+			app.Logger().Info("Removing orphan blob", "key", obj.Key)
+
+			err := app.Storage().Delete(ctx, obj.Key)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	for _, img := range allImages {
+		if stored[ImageKey(img)] {
+			continue
+		}
+
+		report.DanglingImages = append(report.DanglingImages, GCDanglingImage{
+			CollectionId: img.CollectionId,
+			ImageId:      img.Id,
+			Filename:     img.Filename,
+		})
+	}
+
+	if !dryRun {
+		for _, img := range report.DanglingImages {
+			app.Logger().Info("Removing dangling image", "collectionId", img.CollectionId, "imageId", img.ImageId)
+
+			err := removeDanglingImage(ctx, app, img)
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func removeDanglingImage(ctx context.Context, app App, img GCDanglingImage) error {
+	err := RemoveCollectionImage(ctx, app, img.CollectionId, img.ImageId)
+	if err != nil {
+		// NOTE(patrik): Already removed together with the spread it
+		// belongs to
+		if errors.Is(err, database.ErrItemNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func gcTask(ctx context.Context, app App) error {
+	report, err := CollectGarbage(ctx, app, false)
+	if err != nil {
+		return err
+	}
+
+	if len(report.OrphanFiles) > 0 || len(report.DanglingImages) > 0 || report.UnreferencedBlobs > 0 {
+		app.Logger().Info(
+			"Collected garbage",
+			"orphanFiles", len(report.OrphanFiles),
+			"danglingImages", len(report.DanglingImages),
+			"unreferencedBlobs", report.UnreferencedBlobs,
+			"freedBytes", report.FreedBytes,
+		)
+	}
+
+	return nil
+}
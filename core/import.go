@@ -0,0 +1,363 @@
+package core
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/maruel/natural"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+const JobTypeImportArchive = "import-archive"
+
+type ImportArchivePayload struct {
+	CollectionId string `json:"collectionId"`
+	File         string `json:"file"`
+
+	// NOTE(patrik): Remove the archive after a successful import, used
+	// for uploads stored inside the uploads dir
+	RemoveFile bool `json:"removeFile"`
+
+	// NOTE(patrik): Set for the archives picked up from a watch directory,
+	// the archive is moved into one of these under its original name
+	// after the import
+	Name         string `json:"name,omitempty"`
+	ProcessedDir string `json:"processedDir,omitempty"`
+	FailedDir    string `json:"failedDir,omitempty"`
+}
+
+type ProgressFunc func(current, total int)
+
+func IsArchiveExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".zip", ".cbz":
+		return true
+	default:
+		return false
+	}
+}
+
+func isArchiveImage(name string) bool {
+	if strings.HasPrefix(name, "__MACOSX/") {
+		return false
+	}
+
+	if strings.HasPrefix(path.Base(name), ".") {
+		return false
+	}
+
+	return utils.IsImageExt(path.Ext(name))
+}
+
+// importSource is a single image inside an archive or a directory
+type importSource struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+// ImportArchive imports all the images inside a zip archive into the
+// collection, the images are added after the current last image. Returns
+// the number of imported images
+func ImportArchive(ctx context.Context, app App, collectionId, archivePath string, progress ProgressFunc) (int, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var sources []importSource
+	for _, zf := range r.File {
+		if zf.FileInfo().IsDir() || !isArchiveImage(zf.Name) {
+			continue
+		}
+
+		sources = append(sources, importSource{
+			name: zf.Name,
+			open: zf.Open,
+		})
+	}
+
+	return importSources(ctx, app, collectionId, sources, progress)
+}
+
+// ImportFolder imports the images directly inside the directory into the
+// collection, sub directories are not included. Returns the number of
+// imported images
+func ImportFolder(ctx context.Context, app App, collectionId, dir string, progress ProgressFunc) (int, error) {
+	names, err := folderImages(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	sources := make([]importSource, 0, len(names))
+	for _, name := range names {
+		p := path.Join(dir, name)
+
+		sources = append(sources, importSource{
+			name: name,
+			open: func() (io.ReadCloser, error) {
+				return os.Open(p)
+			},
+		})
+	}
+
+	return importSources(ctx, app, collectionId, sources, progress)
+}
+
+// folderImages returns the names of the images directly inside the
+// directory
+func folderImages(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isArchiveImage(entry.Name()) {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
+
+// NOTE(patrik): Imports into the same collection runs one at a time so
+// the pages of two archives uploaded together doesn't get mixed
+type collectionLocks struct {
+	mu    sync.Mutex
+	locks map[string]*collectionLock
+}
+
+type collectionLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+var importLocks = collectionLocks{
+	locks: make(map[string]*collectionLock),
+}
+
+func (l *collectionLocks) lock(id string) func() {
+	l.mu.Lock()
+	lock, exists := l.locks[id]
+	if !exists {
+		lock = &collectionLock{}
+		l.locks[id] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		l.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, id)
+		}
+		l.mu.Unlock()
+	}
+}
+
+func importSources(ctx context.Context, app App, collectionId string, sources []importSource, progress ProgressFunc) (int, error) {
+	unlock := importLocks.lock(collectionId)
+	defer unlock()
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return natural.Less(sources[i].name, sources[j].name)
+	})
+
+	collectionDir := app.WorkDir().CollectionDirById(collectionId)
+	err := collectionDir.Create()
+	if err != nil {
+		return 0, err
+	}
+
+	alg := app.Config().HashAlgorithm
+
+	imported := 0
+
+	importFile := func(source importSource) error {
+		r, err := source.open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		ext := strings.ToLower(path.Ext(source.name))
+		hash, err := StoreBlob(ctx, app, data, alg)
+		if err != nil {
+			return err
+		}
+
+		// NOTE(patrik): Images that can't be decoded are still imported,
+		// the analyze job will try again later
+		analysis, err := AnalyzeImage(data)
+		if err != nil {
+			app.Logger().Warn("Failed to analyze image", "file", source.name, "err", err)
+		}
+
+		_, err = app.DB().CreateImage(ctx, database.CreateImageParams{
+			CollectionId:  collectionId,
+			Hash:          hash,
+			HashAlgorithm: alg,
+			PHash:         analysis.PHash,
+			Width:         analysis.Width,
+			Height:        analysis.Height,
+			Size:          analysis.Size,
+			MimeType:      analysis.MimeType,
+			ColorMode:     analysis.ColorMode,
+			Animated:      analysis.Animated,
+			Filename:      hash + ext,
+			Append:        true,
+		})
+		if err != nil {
+			// NOTE(patrik): A collection can only contain an image once,
+			// archives often repeats the same blank page. The positions
+			// are unique as well so check that it's the hash that exists
+			if errors.Is(err, database.ErrItemAlreadyExists) {
+				_, hashErr := app.DB().GetImageByHash(ctx, collectionId, hash)
+				if hashErr == nil {
+					app.Logger().Warn("Skipping duplicated image", "file", source.name, "collectionId", collectionId)
+					return nil
+				}
+			}
+
+			return err
+		}
+
+		imported++
+
+		return nil
+	}
+
+	for i, source := range sources {
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+
+		err := importFile(source)
+		if err != nil {
+			return imported, err
+		}
+
+		if progress != nil {
+			progress(i+1, len(sources))
+		}
+	}
+
+	return imported, nil
+}
+
+func importArchiveJob(job *JobContext) error {
+	var payload ImportArchivePayload
+	err := job.Payload(&payload)
+	if err != nil {
+		return err
+	}
+
+	app := job.App()
+
+	name := payload.Name
+	if name == "" {
+		name = path.Base(payload.File)
+	}
+
+	job.Log("Importing '%s'", name)
+
+	progress := func(current, total int) {
+		job.SetProgress(current, total)
+
+		app.Broker().EmitEvent(ImportProgressEvent{
+			JobId:        job.Id(),
+			CollectionId: payload.CollectionId,
+			Current:      current,
+			Total:        total,
+		})
+	}
+
+	count, err := ImportArchive(job, app, payload.CollectionId, payload.File, progress)
+	if err != nil {
+		if payload.FailedDir != "" {
+			// NOTE(patrik): The collection was created for the watched
+			// archive so remove it instead of leaving an empty collection,
+			// the job context can already be cancelled here
+			purgeErr := discardCollection(context.Background(), app, payload.CollectionId)
+			if purgeErr != nil {
+				job.Log("Failed to remove collection: %v", purgeErr)
+			}
+
+			moveErr := moveArchive(payload.File, payload.FailedDir, name)
+			if moveErr != nil {
+				job.Log("Failed to move archive: %v", moveErr)
+			}
+		}
+
+		return err
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionUpdated,
+		CollectionId: payload.CollectionId,
+	})
+
+	switch {
+	case payload.ProcessedDir != "":
+		err = moveArchive(payload.File, payload.ProcessedDir, name)
+		if err != nil {
+			job.Log("Failed to move archive: %v", err)
+		}
+	case payload.RemoveFile:
+		err = os.Remove(payload.File)
+		if err != nil {
+			job.Log("Failed to remove archive: %v", err)
+		}
+	}
+
+	jobId, err := app.Jobs().Enqueue(context.Background(), JobTypeGenerateThumbnails, GenerateThumbnailsPayload{
+		CollectionId: payload.CollectionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	job.Log("Queued thumbnail generation (%s)", jobId)
+
+	collection, err := app.DB().GetCollectionById(context.Background(), payload.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	_, err = CreateNotification(context.Background(), app, NotificationParams{
+		Type:         types.NotificationTypeGeneric,
+		Title:        "Import finished",
+		Message:      fmt.Sprintf("Imported %d images into '%s'", count, collection.Title),
+		CollectionId: collection.Id,
+		JobId:        job.Id(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/nanoteck137/storebook/database"
+)
+
+func writeTestArchive(t *testing.T, p string, pages int, seed int) {
+	t.Helper()
+
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for i := range pages {
+		w, err := zw.Create(fmt.Sprintf("%03d.png", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		// NOTE(patrik): The size tells the archives apart after the import
+		size := 8 + seed
+		err = png.Encode(&buf, grayImage(size, size, func(x, y int) uint8 { return uint8(i*7 + x + y) }))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = w.Write(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentImportsKeepArchivesTogether(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: "Test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const pages = 50
+
+	dir := t.TempDir()
+	archives := []string{path.Join(dir, "a.cbz"), path.Join(dir, "b.cbz")}
+	for i, p := range archives {
+		writeTestArchive(t, p, pages, i+1)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(archives))
+	for i, p := range archives {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = ImportArchive(ctx, app, collectionId, p, nil)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("failed to import: %v", err)
+		}
+	}
+
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != len(archives)*pages {
+		t.Fatalf("expected %d images, got %d", len(archives)*pages, len(images))
+	}
+
+	// NOTE(patrik): Each archive needs to be in one piece
+	changes := 0
+	for i, img := range images {
+		if img.Position != i {
+			t.Fatalf("image %d has position %d", i, img.Position)
+		}
+
+		if i > 0 && img.Width.Int64 != images[i-1].Width.Int64 {
+			changes++
+		}
+	}
+
+	if changes != 1 {
+		t.Fatalf("the pages of the archives are mixed")
+	}
+}
@@ -0,0 +1,416 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+const jobPollInterval = 10 * time.Second
+
+var ErrJobNotCancellable = errors.New("core: job is not cancellable")
+var ErrJobNotRetryable = errors.New("core: job is not retryable")
+var ErrUnknownJobType = errors.New("core: unknown job type")
+
+type JobHandlerFunc func(job *JobContext) error
+
+// JobContext is handed to the job handlers and is used to read the payload
+// and to report progress and logs back to the job
+type JobContext struct {
+	context.Context
+
+	app App
+	job database.Job
+}
+
+func (j *JobContext) Id() string {
+	return j.job.Id
+}
+
+func (j *JobContext) App() App {
+	return j.app
+}
+
+func (j *JobContext) Payload(v any) error {
+	return json.Unmarshal([]byte(j.job.Payload), v)
+}
+
+func (j *JobContext) SetResult(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return j.app.DB().UpdateJob(context.Background(), j.job.Id, database.JobChanges{
+		Result: database.Change[sql.NullString]{
+			Value: sql.NullString{
+				String: string(data),
+				Valid:  true,
+			},
+			Changed: true,
+		},
+	})
+}
+
+func (j *JobContext) SetProgress(current, total int) {
+	err := j.app.DB().UpdateJob(context.Background(), j.job.Id, database.JobChanges{
+		ProgressCurrent: database.Change[int]{
+			Value:   current,
+			Changed: true,
+		},
+		ProgressTotal: database.Change[int]{
+			Value:   total,
+			Changed: true,
+		},
+	})
+	if err != nil {
+		j.app.Logger().Error("Failed to update job progress", "jobId", j.job.Id, "err", err)
+	}
+}
+
+func (j *JobContext) Log(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+
+	err := j.app.DB().CreateJobLog(context.Background(), j.job.Id, message)
+	if err != nil {
+		j.app.Logger().Error("Failed to write job log", "jobId", j.job.Id, "err", err)
+	}
+}
+
+// JobRunner runs the jobs stored inside the database on a pool of workers,
+// the queue lives inside the database so queued jobs survive a restart
+type JobRunner struct {
+	app App
+
+	handlers map[string]JobHandlerFunc
+
+	wake chan struct{}
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	started bool
+
+	// NOTE(patrik): Jobs cancelled after they was claimed but before the
+	// worker registered the cancel func
+	pendingCancels map[string]bool
+}
+
+func NewJobRunner(app App) *JobRunner {
+	return &JobRunner{
+		app:      app,
+		handlers: make(map[string]JobHandlerFunc),
+		wake:     make(chan struct{}, 1),
+		cancels:  make(map[string]context.CancelFunc),
+
+		pendingCancels: make(map[string]bool),
+	}
+}
+
+func (r *JobRunner) Register(typ string, handler JobHandlerFunc) {
+	r.handlers[typ] = handler
+}
+
+func (r *JobRunner) Enqueue(ctx context.Context, typ string, payload any) (string, error) {
+	if _, exists := r.handlers[typ]; !exists {
+		return "", fmt.Errorf("%w: %s", ErrUnknownJobType, typ)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := r.app.DB().CreateJob(ctx, database.CreateJobParams{
+		Type:    typ,
+		Status:  types.JobStatusQueued,
+		Payload: string(data),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	r.app.Broker().EmitEvent(JobEvent{
+		JobId:  id,
+		Type:   typ,
+		Status: types.JobStatusQueued,
+	})
+
+	r.notify()
+
+	return id, nil
+}
+
+func (r *JobRunner) Cancel(ctx context.Context, id string) error {
+	job, err := r.app.DB().GetJobById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	switch job.Status {
+	case types.JobStatusQueued:
+		err := r.app.DB().CancelQueuedJob(ctx, job.Id)
+		if err == nil {
+			r.app.Broker().EmitEvent(JobEvent{
+				JobId:  job.Id,
+				Type:   job.Type,
+				Status: types.JobStatusCancelled,
+			})
+
+			return nil
+		}
+
+		if !errors.Is(err, database.ErrItemNotFound) {
+			return err
+		}
+
+		// NOTE(patrik): A worker claimed the job after it was read so
+		// cancel it as a running job instead
+		return r.cancelRunning(job.Id)
+	case types.JobStatusRunning:
+		return r.cancelRunning(job.Id)
+	}
+
+	return ErrJobNotCancellable
+}
+
+// NOTE(patrik): The status is set to running when the job is claimed,
+// before the worker has registered the cancel func, so the cancel is
+// recorded and picked up by the worker when it starts the job
+func (r *JobRunner) cancelRunning(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cancel, exists := r.cancels[id]
+	if exists {
+		cancel()
+		return nil
+	}
+
+	r.pendingCancels[id] = true
+
+	return nil
+}
+
+func (r *JobRunner) Retry(ctx context.Context, id string) error {
+	job, err := r.app.DB().GetJobById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if job.Status != types.JobStatusFailed && job.Status != types.JobStatusCancelled {
+		return ErrJobNotRetryable
+	}
+
+	err = r.app.DB().RemoveJobLogs(ctx, job.Id)
+	if err != nil {
+		return err
+	}
+
+	err = r.app.DB().UpdateJob(ctx, job.Id, database.JobChanges{
+		Status:          database.Change[types.JobStatus]{Value: types.JobStatusQueued, Changed: true},
+		Result:          database.Change[sql.NullString]{Changed: true},
+		Error:           database.Change[sql.NullString]{Changed: true},
+		ProgressCurrent: database.Change[int]{Changed: true},
+		ProgressTotal:   database.Change[int]{Changed: true},
+		Started:         database.Change[sql.NullInt64]{Changed: true},
+		Finished:        database.Change[sql.NullInt64]{Changed: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	r.app.Broker().EmitEvent(JobEvent{
+		JobId:  job.Id,
+		Type:   job.Type,
+		Status: types.JobStatusQueued,
+	})
+
+	r.notify()
+
+	return nil
+}
+
+// Start requeues the jobs that was interrupted by the last shutdown and
+// starts the workers
+func (r *JobRunner) Start(numWorkers int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return nil
+	}
+
+	err := r.app.DB().RequeueRunningJobs(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for range numWorkers {
+		go r.worker()
+	}
+
+	r.started = true
+	r.notify()
+
+	return nil
+}
+
+func (r *JobRunner) notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *JobRunner) worker() {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for r.runNext() {
+		}
+
+		select {
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// NOTE(patrik): Returns true if a job was processed
+func (r *JobRunner) runNext() bool {
+	job, err := r.app.DB().ClaimNextJob(context.Background())
+	if err != nil {
+		if !errors.Is(err, database.ErrItemNotFound) {
+			r.app.Logger().Error("Failed to claim job", "err", err)
+		}
+
+		return false
+	}
+
+	// NOTE(patrik): There might be more jobs in the queue so let the
+	// other workers know
+	r.notify()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r.mu.Lock()
+	r.cancels[job.Id] = cancel
+	if r.pendingCancels[job.Id] {
+		delete(r.pendingCancels, job.Id)
+		cancel()
+	}
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.cancels, job.Id)
+		r.mu.Unlock()
+	}()
+
+	r.app.Logger().Info("Running job", "jobId", job.Id, "type", job.Type)
+
+	r.app.Broker().EmitEvent(JobEvent{
+		JobId:  job.Id,
+		Type:   job.Type,
+		Status: types.JobStatusRunning,
+	})
+
+	err = r.run(ctx, job)
+
+	status := types.JobStatusSuccess
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled):
+		status = types.JobStatusCancelled
+	default:
+		status = types.JobStatusFailed
+		r.app.Logger().Error("Job failed", "jobId", job.Id, "type", job.Type, "err", err)
+	}
+
+	err = r.finish(job, status, err)
+	if err != nil {
+		r.app.Logger().Error("Failed to update job status", "jobId", job.Id, "err", err)
+	}
+
+	return true
+}
+
+func (r *JobRunner) run(ctx context.Context, job database.Job) (err error) {
+	handler, exists := r.handlers[job.Type]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrUnknownJobType, job.Type)
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("job panicked: %v", rec)
+		}
+	}()
+
+	return handler(&JobContext{
+		Context: ctx,
+		app:     r.app,
+		job:     job,
+	})
+}
+
+func (r *JobRunner) finish(job database.Job, status types.JobStatus, jobErr error) error {
+	errStr := sql.NullString{}
+	if jobErr != nil {
+		errStr = sql.NullString{
+			String: jobErr.Error(),
+			Valid:  true,
+		}
+	}
+
+	err := r.app.DB().UpdateJob(context.Background(), job.Id, database.JobChanges{
+		Status: database.Change[types.JobStatus]{
+			Value:   status,
+			Changed: true,
+		},
+		Error: database.Change[sql.NullString]{
+			Value:   errStr,
+			Changed: true,
+		},
+		Finished: database.Change[sql.NullInt64]{
+			Value: sql.NullInt64{
+				Int64: time.Now().UnixMilli(),
+				Valid: true,
+			},
+			Changed: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	r.app.Broker().EmitEvent(JobEvent{
+		JobId:  job.Id,
+		Type:   job.Type,
+		Status: status,
+		Error:  errStr.String,
+	})
+
+	if status == types.JobStatusFailed {
+		_, err := CreateNotification(context.Background(), r.app, NotificationParams{
+			Type:    types.NotificationTypeGeneric,
+			Title:   "Job failed",
+			Message: fmt.Sprintf("%s: %s", job.Type, errStr.String),
+			JobId:   job.Id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+const testJobType = "test"
+
+func waitForJobStatus(t *testing.T, app App, id string, status types.JobStatus) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := app.DB().GetJobById(context.Background(), id)
+		if err != nil {
+			t.Fatalf("failed to get job: %v", err)
+		}
+
+		if job.Status == status {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("job status is %q, expected %q", job.Status, status)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func blockingJob(job *JobContext) error {
+	<-job.Done()
+	return job.Err()
+}
+
+func TestCancelQueuedJob(t *testing.T) {
+	app := newTestApp(t)
+	app.Jobs().Register(testJobType, blockingJob)
+
+	ctx := context.Background()
+
+	id, err := app.Jobs().Enqueue(ctx, testJobType, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.Jobs().Cancel(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForJobStatus(t, app, id, types.JobStatusCancelled)
+
+	_, err = app.DB().ClaimNextJob(ctx)
+	if !errors.Is(err, database.ErrItemNotFound) {
+		t.Fatalf("expected the cancelled job to not be claimed, got %v", err)
+	}
+}
+
+func TestCancelQueuedJobDoesntOverwriteClaimed(t *testing.T) {
+	app := newTestApp(t)
+	app.Jobs().Register(testJobType, blockingJob)
+
+	ctx := context.Background()
+
+	id, err := app.Jobs().Enqueue(ctx, testJobType, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := app.DB().ClaimNextJob(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Id != id {
+		t.Fatalf("claimed %s, expected %s", job.Id, id)
+	}
+
+	err = app.DB().CancelQueuedJob(ctx, id)
+	if !errors.Is(err, database.ErrItemNotFound) {
+		t.Fatalf("expected ErrItemNotFound, got %v", err)
+	}
+
+	waitForJobStatus(t, app, id, types.JobStatusRunning)
+}
+
+func TestCancelJobClaimedBeforeRunning(t *testing.T) {
+	app := newTestApp(t)
+	app.Jobs().Register(testJobType, blockingJob)
+
+	ctx := context.Background()
+
+	id, err := app.Jobs().Enqueue(ctx, testJobType, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE(patrik): Same as Cancel losing the race against a worker that
+	// hasn't registered the cancel func yet
+	err = app.Jobs().cancelRunning(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !app.Jobs().runNext() {
+		t.Fatal("expected a job to run")
+	}
+
+	waitForJobStatus(t, app, id, types.JobStatusCancelled)
+}
+
+func TestCancelClaimedJob(t *testing.T) {
+	app := newTestApp(t)
+	app.Jobs().Register(testJobType, blockingJob)
+
+	ctx := context.Background()
+
+	id, err := app.Jobs().Enqueue(ctx, testJobType, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE(patrik): The job is marked as running but no worker has
+	// started it yet
+	_, err = app.DB().ClaimNextJob(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.Jobs().Cancel(ctx, id)
+	if err != nil {
+		t.Fatalf("expected the claimed job to be cancellable, got %v", err)
+	}
+
+	app.Jobs().mu.Lock()
+	pending := app.Jobs().pendingCancels[id]
+	app.Jobs().mu.Unlock()
+
+	if !pending {
+		t.Fatal("expected the cancel to be recorded")
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	app := newTestApp(t)
+	app.Jobs().Register(testJobType, blockingJob)
+
+	ctx := context.Background()
+
+	id, err := app.Jobs().Enqueue(ctx, testJobType, struct{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.Jobs().Start(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForJobStatus(t, app, id, types.JobStatusRunning)
+
+	err = app.Jobs().Cancel(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waitForJobStatus(t, app, id, types.JobStatusCancelled)
+}
@@ -0,0 +1,397 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/maruel/natural"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+// NOTE(patrik): The source hash always uses sha256 so changing the hash
+// algorithm inside the config doesn't make the import see everything as
+// new
+const librarySourceHashAlgorithm = types.HashAlgorithmSHA256
+
+var ErrNoImages = errors.New("no images found")
+
+var (
+	volumeRegex     = regexp.MustCompile(`(?i)(?:^|[^a-z])(?:v|vol|volume)\.?\s*(\d+)`)
+	onlyNumberRegex = regexp.MustCompile(`^#?(\d+)$`)
+)
+
+// LibraryItem is an archive or a folder of images that becomes a single
+// collection
+type LibraryItem struct {
+	Path   string
+	Folder bool
+
+	Title  string
+	Series string
+	Volume sql.NullInt64
+}
+
+type LibraryImportResult struct {
+	Item LibraryItem
+
+	CollectionId string
+	Images       int
+
+	// NOTE(patrik): Set when a collection has already been imported from
+	// the same content
+	Skipped bool
+	Err     error
+}
+
+type LibraryImportReport struct {
+	Imported int
+	Skipped  int
+	Images   int
+
+	Failed []LibraryImportResult
+}
+
+// ScanLibrary walks the directory and returns the archives and the
+// folders that directly contains images, hidden files and directories
+// are ignored
+func ScanLibrary(root string) ([]LibraryItem, error) {
+	var items []LibraryItem
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "__MACOSX") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.IsDir() {
+			if IsArchiveExt(path.Ext(d.Name())) {
+				items = append(items, newLibraryItem(root, p, false))
+			}
+
+			return nil
+		}
+
+		images, err := folderImages(p)
+		if err != nil {
+			return err
+		}
+
+		if len(images) > 0 {
+			items = append(items, newLibraryItem(root, p, true))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// parseVolume returns the volume number inside the name, names that only
+// contains a number are treated as the volume as well
+func parseVolume(name string) (int64, bool) {
+	match := onlyNumberRegex.FindStringSubmatch(name)
+	if match == nil {
+		match = volumeRegex.FindStringSubmatch(name)
+	}
+
+	if match == nil {
+		return 0, false
+	}
+
+	volume, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return volume, true
+}
+
+// newLibraryItem derives the series and the volume from the path, the
+// series is the closest parent directory that isn't a volume directory
+// and the volume is taken from the name of the item or the volume
+// directory it's inside of.
+//
+//	Berserk/Berserk v01.cbz   -> series "Berserk", volume 1
+//	Berserk/Vol 02/           -> series "Berserk", volume 2
+//	Berserk/03.cbz            -> series "Berserk", volume 3
+func newLibraryItem(root, p string, folder bool) LibraryItem {
+	item := LibraryItem{
+		Path:   p,
+		Folder: folder,
+	}
+
+	name := filepath.Base(p)
+	if folder {
+		item.Title = utils.FixSpaces(strings.ReplaceAll(name, "_", " "))
+	} else {
+		item.Title = TitleFromFilename(name)
+	}
+
+	if volume, ok := parseVolume(item.Title); ok {
+		item.Volume = sql.NullInt64{Int64: volume, Valid: true}
+	}
+
+	rel, err := filepath.Rel(root, filepath.Dir(p))
+	if err != nil || rel == "." {
+		return item
+	}
+
+	parents := strings.Split(filepath.ToSlash(rel), "/")
+	for i := len(parents) - 1; i >= 0; i-- {
+		parent := utils.FixSpaces(strings.ReplaceAll(parents[i], "_", " "))
+
+		if volume, ok := parseVolume(parent); ok {
+			if !item.Volume.Valid {
+				item.Volume = sql.NullInt64{Int64: volume, Valid: true}
+			}
+
+			continue
+		}
+
+		item.Series = parent
+		break
+	}
+
+	if item.Series != "" && !strings.Contains(strings.ToLower(item.Title), strings.ToLower(item.Series)) {
+		item.Title = item.Series + " - " + item.Title
+	}
+
+	return item
+}
+
+// librarySourceHash hashes the archive or the images of the folder in the
+// order they are imported
+func librarySourceHash(item LibraryItem) (string, error) {
+	if !item.Folder {
+		f, err := os.Open(item.Path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		return utils.HashReader(f, librarySourceHashAlgorithm)
+	}
+
+	names, err := folderImages(item.Path)
+	if err != nil {
+		return "", err
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return natural.Less(names[i], names[j])
+	})
+
+	h, err := utils.NewHasher(librarySourceHashAlgorithm)
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range names {
+		err := func() error {
+			f, err := os.Open(filepath.Join(item.Path, name))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			_, err = io.Copy(h, f)
+			return err
+		}()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type libraryImporter struct {
+	app App
+
+	mu      sync.Mutex
+	claimed map[string]bool
+}
+
+// claim makes sure the same content found twice inside the tree is only
+// imported once
+func (l *libraryImporter) claim(ctx context.Context, hash string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.claimed[hash] {
+		return false, nil
+	}
+
+	exists, err := l.app.DB().HasCollectionWithSourceHash(ctx, hash)
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, nil
+	}
+
+	l.claimed[hash] = true
+	return true, nil
+}
+
+func (l *libraryImporter) importItem(ctx context.Context, item LibraryItem) LibraryImportResult {
+	res := LibraryImportResult{
+		Item: item,
+	}
+
+	hash, err := librarySourceHash(item)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	claimed, err := l.claim(ctx, hash)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if !claimed {
+		res.Skipped = true
+		return res
+	}
+
+	app := l.app
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title:  item.Title,
+		Series: sql.NullString{String: item.Series, Valid: item.Series != ""},
+		Volume: item.Volume,
+	})
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	var count int
+	if item.Folder {
+		count, err = ImportFolder(ctx, app, collectionId, item.Path, nil)
+	} else {
+		count, err = ImportArchive(ctx, app, collectionId, item.Path, nil)
+	}
+
+	if err == nil && count == 0 {
+		err = ErrNoImages
+	}
+
+	// NOTE(patrik): The source hash is only set after the images has been
+	// imported so an interrupted import is imported again on the next run
+	if err == nil {
+		err = app.DB().UpdateCollection(ctx, collectionId, database.CollectionChanges{
+			SourceHash: database.Change[sql.NullString]{
+				Value:   sql.NullString{String: hash, Valid: true},
+				Changed: true,
+			},
+		})
+	}
+
+	if err != nil {
+		res.Err = errors.Join(err, PurgeCollection(context.Background(), app, collectionId))
+		return res
+	}
+
+	// NOTE(patrik): Picked up by the job runner the next time the server
+	// is started
+	_, err = app.Jobs().Enqueue(ctx, JobTypeGenerateThumbnails, GenerateThumbnailsPayload{
+		CollectionId: collectionId,
+	})
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.CollectionId = collectionId
+	res.Images = count
+
+	return res
+}
+
+// ImportLibrary imports the items using multiple workers, the items
+// already imported are skipped. The callback is called after each item
+func ImportLibrary(ctx context.Context, app App, items []LibraryItem, workers int, done func(res LibraryImportResult)) LibraryImportReport {
+	importer := &libraryImporter{
+		app:     app,
+		claimed: make(map[string]bool),
+	}
+
+	queue := make(chan LibraryItem)
+	results := make(chan LibraryImportResult)
+
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for item := range queue {
+				results <- importer.importItem(ctx, item)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+
+		for _, item := range items {
+			select {
+			case queue <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var report LibraryImportReport
+	for res := range results {
+		switch {
+		case res.Err != nil:
+			report.Failed = append(report.Failed, res)
+		case res.Skipped:
+			report.Skipped++
+		default:
+			report.Imported++
+			report.Images += res.Images
+		}
+
+		if done != nil {
+			done(res)
+		}
+	}
+
+	return report
+}
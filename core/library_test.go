@@ -0,0 +1,129 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestParseVolume(t *testing.T) {
+	tests := []struct {
+		name   string
+		volume int64
+		ok     bool
+	}{
+		{"Berserk v01", 1, true},
+		{"Vol 02", 2, true},
+		{"Vol. 3", 3, true},
+		{"Volume 12", 12, true},
+		{"03", 3, true},
+		{"#5", 5, true},
+		{"Berserk", 0, false},
+		{"Chapter 1", 0, false},
+		{"Devil 2", 0, false},
+	}
+
+	for _, test := range tests {
+		volume, ok := parseVolume(test.name)
+		if ok != test.ok || volume != test.volume {
+			t.Errorf("parseVolume(%q) = %d, %v, expected %d, %v", test.name, volume, ok, test.volume, test.ok)
+		}
+	}
+}
+
+func TestScanLibrary(t *testing.T) {
+	root := t.TempDir()
+
+	files := []string{
+		"Berserk/Berserk v01.cbz",
+		"Berserk/Vol 02/001.png",
+		"Berserk/Vol 02/002.png",
+		"Berserk/03.cbz",
+		"Berserk/Vol 04/Chapter 1.cbz",
+		"Berserk/._04.cbz",
+		"My_Series/Extra.zip",
+		"Loose_One.cbz",
+		"notes.txt",
+		".hidden/Hidden.cbz",
+		"__MACOSX/Berserk v01.cbz",
+	}
+
+	for _, file := range files {
+		p := filepath.Join(root, file)
+
+		err := os.MkdirAll(filepath.Dir(p), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = os.WriteFile(p, nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	items, err := ScanLibrary(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Path < items[j].Path
+	})
+
+	type expectedItem struct {
+		path   string
+		folder bool
+		title  string
+		series string
+		volume int64
+	}
+
+	expected := []expectedItem{
+		{"Berserk/03.cbz", false, "Berserk - 03", "Berserk", 3},
+		{"Berserk/Berserk v01.cbz", false, "Berserk v01", "Berserk", 1},
+		{"Berserk/Vol 02", true, "Berserk - Vol 02", "Berserk", 2},
+		{"Berserk/Vol 04/Chapter 1.cbz", false, "Berserk - Chapter 1", "Berserk", 4},
+		{"Loose_One.cbz", false, "Loose One", "", 0},
+		{"My_Series/Extra.zip", false, "My Series - Extra", "My Series", 0},
+	}
+
+	if len(items) != len(expected) {
+		for _, item := range items {
+			t.Log(item.Path)
+		}
+
+		t.Fatalf("got %d items, expected %d", len(items), len(expected))
+	}
+
+	for i, e := range expected {
+		item := items[i]
+
+		rel, err := filepath.Rel(root, item.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rel != e.path {
+			t.Errorf("item %d: path %q, expected %q", i, rel, e.path)
+			continue
+		}
+
+		if item.Folder != e.folder {
+			t.Errorf("%s: folder %v, expected %v", e.path, item.Folder, e.folder)
+		}
+
+		if item.Title != e.title {
+			t.Errorf("%s: title %q, expected %q", e.path, item.Title, e.title)
+		}
+
+		if item.Series != e.series {
+			t.Errorf("%s: series %q, expected %q", e.path, item.Series, e.series)
+		}
+
+		if item.Volume.Valid != (e.volume != 0) || item.Volume.Int64 != e.volume {
+			t.Errorf("%s: volume %v, expected %d", e.path, item.Volume, e.volume)
+		}
+	}
+}
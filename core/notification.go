@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+type NotificationParams struct {
+	Type types.NotificationType
+
+	Title   string
+	Message string
+
+	CollectionId string
+	JobId        string
+}
+
+// CreateNotification stores the notification and pushes it to the
+// connected clients
+func CreateNotification(ctx context.Context, app App, params NotificationParams) (string, error) {
+	typ := params.Type
+	if typ == "" {
+		typ = types.NotificationTypeGeneric
+	}
+
+	id, err := app.DB().CreateNotification(ctx, database.CreateNotificationParams{
+		Type:    typ,
+		Title:   params.Title,
+		Message: params.Message,
+		CollectionId: sql.NullString{
+			String: params.CollectionId,
+			Valid:  params.CollectionId != "",
+		},
+		JobId: sql.NullString{
+			String: params.JobId,
+			Valid:  params.JobId != "",
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	app.Broker().EmitEvent(NotificationEvent{
+		NotificationId: id,
+		Type:           typ,
+		Title:          params.Title,
+		Message:        params.Message,
+	})
+
+	return id, nil
+}
@@ -0,0 +1,297 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"github.com/nanoteck137/storebook/database"
+	"golang.org/x/image/draw"
+)
+
+// NOTE(patrik): Default max hamming distance between two perceptual hashes
+// for the images to count as near duplicates
+const DefaultDuplicateThreshold = 6
+
+// DHash computes the difference hash of the image, the image is scaled
+// down to 9x8 grayscale pixels and each bit tells if a pixel is brighter
+// than its right neighbour
+func DHash(src image.Image) uint64 {
+	gray := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.BiLinear.Scale(gray, gray.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if gray.GrayAt(x, y).Y > gray.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash
+}
+
+func FormatPHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+func ParsePHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// NOTE(patrik): BK-tree over the hamming distance so we don't need to
+// compare every image with every other image
+type bkNode struct {
+	hash     uint64
+	items    []int
+	children map[int]*bkNode
+}
+
+type bkTree struct {
+	root *bkNode
+}
+
+func (t *bkTree) insert(hash uint64, item int) {
+	if t.root == nil {
+		t.root = &bkNode{hash: hash, items: []int{item}}
+		return
+	}
+
+	node := t.root
+	for {
+		d := HammingDistance(hash, node.hash)
+		if d == 0 {
+			node.items = append(node.items, item)
+			return
+		}
+
+		child, exists := node.children[d]
+		if !exists {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+
+			node.children[d] = &bkNode{hash: hash, items: []int{item}}
+			return
+		}
+
+		node = child
+	}
+}
+
+func (t *bkTree) search(hash uint64, threshold int, fn func(item, distance int)) {
+	if t.root == nil {
+		return
+	}
+
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := HammingDistance(hash, node.hash)
+		if d <= threshold {
+			for _, item := range node.items {
+				fn(item, d)
+			}
+		}
+
+		for cd, child := range node.children {
+			if cd >= d-threshold && cd <= d+threshold {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+type phashIndex struct {
+	images []database.Image
+	hashes []uint64
+	tree   bkTree
+}
+
+func newPHashIndex(images []database.Image) *phashIndex {
+	index := &phashIndex{}
+
+	for _, img := range images {
+		if !img.PHash.Valid {
+			continue
+		}
+
+		hash, err := ParsePHash(img.PHash.String)
+		if err != nil {
+			continue
+		}
+
+		index.tree.insert(hash, len(index.images))
+		index.images = append(index.images, img)
+		index.hashes = append(index.hashes, hash)
+	}
+
+	return index
+}
+
+// FindNearDuplicateImages groups the images where each image is within the
+// threshold of at least one other image inside the group, only the groups
+// with an image accepted by filter are returned (nil accepts all)
+func FindNearDuplicateImages(images []database.Image, threshold int, filter func(img database.Image) bool) [][]database.Image {
+	index := newPHashIndex(images)
+
+	parent := make([]int, len(index.images))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+
+		return i
+	}
+
+	for i, hash := range index.hashes {
+		index.tree.search(hash, threshold, func(j, _ int) {
+			if i == j {
+				return
+			}
+
+			a, b := find(i), find(j)
+			if a != b {
+				parent[b] = a
+			}
+		})
+	}
+
+	groups := make(map[int][]int)
+	var order []int
+	for i := range index.images {
+		root := find(i)
+		if _, exists := groups[root]; !exists {
+			order = append(order, root)
+		}
+
+		groups[root] = append(groups[root], i)
+	}
+
+	var res [][]database.Image
+	for _, root := range order {
+		members := groups[root]
+		if len(members) < 2 {
+			continue
+		}
+
+		group := make([]database.Image, len(members))
+		accepted := filter == nil
+		for i, member := range members {
+			group[i] = index.images[member]
+
+			if !accepted && filter(group[i]) {
+				accepted = true
+			}
+		}
+
+		if accepted {
+			res = append(res, group)
+		}
+	}
+
+	return res
+}
+
+type CollectionOverlap struct {
+	CollectionA string
+	CollectionB string
+
+	// NOTE(patrik): Number of images inside the collection that has a near
+	// duplicate inside the other collection
+	MatchedA int
+	MatchedB int
+
+	TotalA int
+	TotalB int
+}
+
+// Overlap returns the fraction of the smaller collection that is found
+// inside the other collection
+func (o CollectionOverlap) Overlap() float64 {
+	a := float64(o.MatchedA) / float64(max(o.TotalA, 1))
+	b := float64(o.MatchedB) / float64(max(o.TotalB, 1))
+
+	return max(a, b)
+}
+
+// FindDuplicateCollections finds the pairs of collections where at least
+// minOverlap of the pages of one collection has a near duplicate inside
+// the other
+func FindDuplicateCollections(images []database.Image, threshold int, minOverlap float64) []CollectionOverlap {
+	index := newPHashIndex(images)
+
+	totals := make(map[string]int)
+	for _, img := range index.images {
+		totals[img.CollectionId]++
+	}
+
+	type pair struct {
+		a, b string
+	}
+
+	// NOTE(patrik): matched[pair{a, b}] is the set of images inside a that
+	// has a near duplicate inside b
+	matched := make(map[pair]map[int]bool)
+
+	for i, hash := range index.hashes {
+		a := index.images[i].CollectionId
+
+		index.tree.search(hash, threshold, func(j, _ int) {
+			b := index.images[j].CollectionId
+			if a == b {
+				return
+			}
+
+			key := pair{a, b}
+			if matched[key] == nil {
+				matched[key] = make(map[int]bool)
+			}
+
+			matched[key][i] = true
+		})
+	}
+
+	var res []CollectionOverlap
+	for key, set := range matched {
+		// NOTE(patrik): Only handle each pair once
+		if key.a > key.b {
+			continue
+		}
+
+		overlap := CollectionOverlap{
+			CollectionA: key.a,
+			CollectionB: key.b,
+			MatchedA:    len(set),
+			MatchedB:    len(matched[pair{key.b, key.a}]),
+			TotalA:      totals[key.a],
+			TotalB:      totals[key.b],
+		}
+
+		if overlap.Overlap() >= minOverlap {
+			res = append(res, overlap)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Overlap() > res[j].Overlap()
+	})
+
+	return res
+}
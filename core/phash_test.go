@@ -0,0 +1,195 @@
+package core
+
+import (
+	"database/sql"
+	"image"
+	"image/color"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nanoteck137/storebook/database"
+)
+
+func testImage(id, collectionId string, hash uint64) database.Image {
+	return database.Image{
+		Id:           id,
+		CollectionId: collectionId,
+		PHash:        sql.NullString{String: FormatPHash(hash), Valid: true},
+	}
+}
+
+func TestBKTreeSearchMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	hashes := make([]uint64, 500)
+	for i := range hashes {
+		// NOTE(patrik): Flip a few bits of a small set of base hashes so
+		// there is something within the thresholds
+		hashes[i] = uint64(rng.Intn(8)) * 0x0123456789abcdef
+		for range rng.Intn(12) {
+			hashes[i] ^= 1 << rng.Intn(64)
+		}
+	}
+
+	var tree bkTree
+	for i, hash := range hashes {
+		tree.insert(hash, i)
+	}
+
+	for _, threshold := range []int{0, 1, 4, 6, 10} {
+		for q := 0; q < 50; q++ {
+			query := hashes[rng.Intn(len(hashes))] ^ (1 << rng.Intn(64))
+
+			var expected []int
+			for i, hash := range hashes {
+				if HammingDistance(query, hash) <= threshold {
+					expected = append(expected, i)
+				}
+			}
+
+			var got []int
+			tree.search(query, threshold, func(item, distance int) {
+				if distance != HammingDistance(query, hashes[item]) {
+					t.Fatalf("item %d reported with distance %d", item, distance)
+				}
+
+				got = append(got, item)
+			})
+
+			sort.Ints(got)
+
+			if len(got) != len(expected) {
+				t.Fatalf("threshold %d: found %d items, expected %d", threshold, len(got), len(expected))
+			}
+
+			for i := range got {
+				if got[i] != expected[i] {
+					t.Fatalf("threshold %d: found %v, expected %v", threshold, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestFindNearDuplicateImages(t *testing.T) {
+	images := []database.Image{
+		// NOTE(patrik): a-c are chained, a and c are too far apart on their
+		// own but ends up in the same group through b
+		testImage("a", "c1", 0x0),
+		testImage("b", "c1", 0xf),
+		testImage("c", "c2", 0xff),
+		testImage("d", "c2", 0xffffffff00000000),
+		testImage("e", "c3", 0xffffffff00000001),
+		testImage("f", "c3", 0x00ff00ff00ff00ff),
+		// NOTE(patrik): Images without a hash is ignored
+		{Id: "g", CollectionId: "c3"},
+	}
+
+	groups := FindNearDuplicateImages(images, 4, nil)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+
+	ids := func(group []database.Image) string {
+		var res []string
+		for _, img := range group {
+			res = append(res, img.Id)
+		}
+		sort.Strings(res)
+
+		return strings.Join(res, ",")
+	}
+
+	got := []string{ids(groups[0]), ids(groups[1])}
+	sort.Strings(got)
+
+	expected := []string{ids(images[0:3]), ids(images[3:5])}
+	sort.Strings(expected)
+
+	if got[0] != expected[0] || got[1] != expected[1] {
+		t.Fatalf("got groups %v, expected %v", got, expected)
+	}
+
+	groups = FindNearDuplicateImages(images, 4, func(img database.Image) bool {
+		return img.CollectionId == "c3"
+	})
+	if len(groups) != 1 || ids(groups[0]) != ids(images[3:5]) {
+		t.Fatalf("filter: unexpected groups %v", groups)
+	}
+}
+
+func TestFindDuplicateCollections(t *testing.T) {
+	var images []database.Image
+
+	// NOTE(patrik): c2 contains 3 of the 4 pages of c1 with a couple of
+	// bits changed, c3 only shares a single page
+	pages := []uint64{0x1111111111111111, 0x2222222222222222, 0x4444444444444444, 0x8888888888888888}
+	for i, hash := range pages {
+		images = append(images, testImage("c1-"+strconv.Itoa(i), "c1", hash))
+	}
+
+	for i, hash := range pages[:3] {
+		images = append(images, testImage("c2-"+strconv.Itoa(i), "c2", hash^0x3))
+	}
+	images = append(images, testImage("c2-x", "c2", 0xf0f0f0f0f0f0f0f0))
+
+	images = append(images,
+		testImage("c3-0", "c3", pages[3]),
+		testImage("c3-1", "c3", 0x0f0f0f0f0f0f0f0f),
+		testImage("c3-2", "c3", 0xaaaaaaaaaaaaaaaa),
+		testImage("c3-3", "c3", 0x5555555555555555),
+	)
+
+	res := FindDuplicateCollections(images, DefaultDuplicateThreshold, 0.5)
+	if len(res) != 1 {
+		t.Fatalf("expected a single pair, got %+v", res)
+	}
+
+	overlap := res[0]
+	if overlap.CollectionA != "c1" || overlap.CollectionB != "c2" {
+		t.Fatalf("unexpected pair %s/%s", overlap.CollectionA, overlap.CollectionB)
+	}
+
+	if overlap.MatchedA != 3 || overlap.MatchedB != 3 || overlap.TotalA != 4 || overlap.TotalB != 4 {
+		t.Fatalf("unexpected overlap %+v", overlap)
+	}
+
+	if overlap.Overlap() != 0.75 {
+		t.Fatalf("expected overlap 0.75, got %f", overlap.Overlap())
+	}
+
+	// NOTE(patrik): c1 and c3 only shares a single page
+	res = FindDuplicateCollections(images, DefaultDuplicateThreshold, 0.2)
+	if len(res) != 2 {
+		t.Fatalf("expected 2 pairs with a lower overlap, got %d", len(res))
+	}
+}
+
+func TestDHashIsStableWhenScaled(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 90, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 90; x++ {
+			src.SetGray(x, y, color.Gray{Y: uint8((x*7 + y*13 + (x*y)%50) % 256)})
+		}
+	}
+
+	scaled := image.NewGray(image.Rect(0, 0, 180, 160))
+	for y := 0; y < 160; y++ {
+		for x := 0; x < 180; x++ {
+			scaled.SetGray(x, y, src.GrayAt(x/2, y/2))
+		}
+	}
+
+	a, b := DHash(src), DHash(scaled)
+	if d := HammingDistance(a, b); d > DefaultDuplicateThreshold {
+		t.Fatalf("scaled image has distance %d", d)
+	}
+
+	hash, err := ParsePHash(FormatPHash(a))
+	if err != nil || hash != a {
+		t.Fatalf("hash didn't survive formatting: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+const (
+	SettingReaderDirection = "reader.direction"
+	SettingReaderLayout    = "reader.layout"
+	SettingReaderFit       = "reader.fit"
+)
+
+type ReaderSettings struct {
+	Direction types.ReadingDirection
+	Layout    types.ReaderLayout
+	Fit       types.ReaderFit
+}
+
+var DefaultReaderSettings = ReaderSettings{
+	Direction: types.ReadingDirectionLTR,
+	Layout:    types.ReaderLayoutSingle,
+	Fit:       types.ReaderFitScreen,
+}
+
+// GetReaderDefaults returns the reader settings used by collections
+// without overrides, settings not stored in the database falls back to
+// DefaultReaderSettings
+func GetReaderDefaults(ctx context.Context, app App) (ReaderSettings, error) {
+	settings, err := app.DB().GetAllSettings(ctx)
+	if err != nil {
+		return ReaderSettings{}, err
+	}
+
+	res := DefaultReaderSettings
+
+	// NOTE(patrik): Ignore invalid values so a bad row can't break the
+	// reader
+	for _, s := range settings {
+		switch s.Key {
+		case SettingReaderDirection:
+			if d := types.ReadingDirection(s.Value); types.IsValidReadingDirection(d) {
+				res.Direction = d
+			}
+		case SettingReaderLayout:
+			if l := types.ReaderLayout(s.Value); types.IsValidReaderLayout(l) {
+				res.Layout = l
+			}
+		case SettingReaderFit:
+			if f := types.ReaderFit(s.Value); types.IsValidReaderFit(f) {
+				res.Fit = f
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// ResolveReaderSettings applies the overrides of the collection on top of
+// the defaults
+func ResolveReaderSettings(defaults ReaderSettings, collection database.Collection) ReaderSettings {
+	res := defaults
+
+	if v := collection.ReaderDirection; v.Valid && types.IsValidReadingDirection(types.ReadingDirection(v.String)) {
+		res.Direction = types.ReadingDirection(v.String)
+	}
+
+	if v := collection.ReaderLayout; v.Valid && types.IsValidReaderLayout(types.ReaderLayout(v.String)) {
+		res.Layout = types.ReaderLayout(v.String)
+	}
+
+	if v := collection.ReaderFit; v.Valid && types.IsValidReaderFit(types.ReaderFit(v.String)) {
+		res.Fit = types.ReaderFit(v.String)
+	}
+
+	return res
+}
+
+func GetCollectionReaderSettings(ctx context.Context, app App, collection database.Collection) (ReaderSettings, error) {
+	defaults, err := GetReaderDefaults(ctx, app)
+	if err != nil {
+		return ReaderSettings{}, err
+	}
+
+	return ResolveReaderSettings(defaults, collection), nil
+}
+
+// ReaderOverrideChange converts a value from the api into a change for
+// the collection, an empty string removes the override
+func ReaderOverrideChange(value string, current sql.NullString) database.Change[sql.NullString] {
+	v := sql.NullString{
+		String: value,
+		Valid:  value != "",
+	}
+
+	return database.Change[sql.NullString]{
+		Value:   v,
+		Changed: v != current,
+	}
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+const JobTypeRehashImages = "rehash-images"
+
+type RehashImagesResult struct {
+	Rehashed int `json:"rehashed"`
+	Failed   int `json:"failed"`
+}
+
+// hashImageWith hashes the image with both the old and the new algorithm
+// in a single pass
+func hashImageWith(ctx context.Context, app App, img database.Image, oldAlg, newAlg types.HashAlgorithm) (string, string, error) {
+	oldHasher, err := utils.NewHasher(oldAlg)
+	if err != nil {
+		return "", "", err
+	}
+
+	newHasher, err := utils.NewHasher(newAlg)
+	if err != nil {
+		return "", "", err
+	}
+
+	r, _, err := OpenImage(ctx, app, img)
+	if err != nil {
+		return "", "", err
+	}
+	defer r.Close()
+
+	_, err = io.Copy(io.MultiWriter(oldHasher, newHasher), r)
+	if err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(oldHasher.Sum(nil)), hex.EncodeToString(newHasher.Sum(nil)), nil
+}
+
+// RehashImage re-hashes the image with the algorithm and moves it to the
+// new blob together with renaming its thumbnails, the file is checked
+// against the old hash first so that corrupted files don't get a new
+// valid hash
+func RehashImage(ctx context.Context, app App, img database.Image, alg types.HashAlgorithm) error {
+	oldHash, newHash, err := hashImageWith(ctx, app, img, img.HashAlgorithm, alg)
+	if err != nil {
+		return err
+	}
+
+	if oldHash != img.Hash {
+		return fmt.Errorf("hash mismatch for '%s', run verify", img.Filename)
+	}
+
+	// NOTE(patrik): Copy instead of moving because other images might
+	// still reference the old blob
+	err = copyBlob(ctx, app, img, alg, newHash)
+	if err != nil {
+		return err
+	}
+
+	filename := newHash + path.Ext(img.Filename)
+
+	err = app.DB().UpdateImage(ctx, img.CollectionId, img.Id, database.ImageChanges{
+		Hash: database.Change[string]{
+			Value:   newHash,
+			Changed: true,
+		},
+		HashAlgorithm: database.Change[types.HashAlgorithm]{
+			Value:   alg,
+			Changed: true,
+		},
+		Filename: database.Change[string]{
+			Value:   filename,
+			Changed: true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	dir := app.WorkDir().CollectionDirById(img.CollectionId)
+	for _, size := range ThumbnailSizes {
+		err := os.Rename(
+			path.Join(dir.Thumbnails(), ThumbnailFilename(img.Hash, size.Name)),
+			path.Join(dir.Thumbnails(), ThumbnailFilename(newHash, size.Name)),
+		)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return RemoveBlobIfUnreferenced(ctx, app, img.HashAlgorithm, img.Hash)
+}
+
+func rehashImagesJob(job *JobContext) error {
+	app := job.App()
+	alg := app.Config().HashAlgorithm
+
+	images, err := app.DB().GetImagesNotUsingHashAlgorithm(job, alg)
+	if err != nil {
+		return err
+	}
+
+	job.Log("Rehashing %d images to %s", len(images), alg)
+
+	var result RehashImagesResult
+	updated := make(map[string]bool)
+
+	for i, img := range images {
+		if err := job.Err(); err != nil {
+			return err
+		}
+
+		err := RehashImage(job, app, img, alg)
+		if err != nil {
+			job.Log("Failed to rehash %s/%s: %v", img.CollectionId, img.Id, err)
+			result.Failed++
+		} else {
+			result.Rehashed++
+			updated[img.CollectionId] = true
+		}
+
+		job.SetProgress(i+1, len(images))
+	}
+
+	for collectionId := range updated {
+		app.Broker().EmitEvent(CollectionEvent{
+			Type:         EventCollectionUpdated,
+			CollectionId: collectionId,
+		})
+	}
+
+	err = job.SetResult(result)
+	if err != nil {
+		return err
+	}
+
+	if result.Failed > 0 {
+		return fmt.Errorf("failed to rehash %d images", result.Failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+type ReleaseSchedule struct {
+	Start        time.Time
+	DelayDays    int
+	IntervalDays int
+
+	// NOTE(patrik): 0 means that the number of parts is unknown
+	NumParts int
+}
+
+// GetReleaseSchedule returns the release schedule of the collection, the
+// bool is false if the collection has no schedule
+func GetReleaseSchedule(collection database.Collection) (ReleaseSchedule, bool) {
+	if !collection.ReleaseStart.Valid {
+		return ReleaseSchedule{}, false
+	}
+
+	start, err := time.Parse(types.MediaDateLayout, collection.ReleaseStart.String)
+	if err != nil {
+		return ReleaseSchedule{}, false
+	}
+
+	return ReleaseSchedule{
+		Start:        start,
+		DelayDays:    collection.ReleaseDelayDays,
+		IntervalDays: max(collection.ReleaseIntervalDays, 1),
+		NumParts:     collection.ReleaseNumParts,
+	}, true
+}
+
+func (s ReleaseSchedule) CurrentPart() int {
+	part := utils.CurrentPart(s.Start, s.DelayDays, s.IntervalDays)
+	if s.NumParts > 0 {
+		part = min(part, s.NumParts)
+	}
+
+	return part
+}
+
+// NextRelease returns the date and the part number of the next release,
+// the bool is false when all the parts have been released
+func (s ReleaseSchedule) NextRelease() (time.Time, int, bool) {
+	part := utils.CurrentPart(s.Start, s.DelayDays, s.IntervalDays) + 1
+	if s.NumParts > 0 && part > s.NumParts {
+		return time.Time{}, 0, false
+	}
+
+	return utils.NextAiringDate(s.Start, s.DelayDays, s.IntervalDays), part, true
+}
+
+func (s ReleaseSchedule) Status() types.MediaPartReleaseStatus {
+	current := s.CurrentPart()
+
+	switch {
+	case current == 0:
+		return types.MediaPartReleaseStatusWaiting
+	case s.NumParts > 0 && current >= s.NumParts:
+		return types.MediaPartReleaseStatusCompleted
+	default:
+		return types.MediaPartReleaseStatusRunning
+	}
+}
+
+// PartDate returns the release date of the part (first part is 1)
+func (s ReleaseSchedule) PartDate(part int) time.Time {
+	effectiveStart := s.Start.Add(time.Duration(s.DelayDays) * 24 * time.Hour)
+	return effectiveStart.Add(time.Duration(part-1) * time.Duration(s.IntervalDays) * 24 * time.Hour)
+}
+
+type Release struct {
+	Part int
+	Date time.Time
+}
+
+// ReleasesBetween returns all the releases inside the range [start, end)
+func (s ReleaseSchedule) ReleasesBetween(start, end time.Time) []Release {
+	var res []Release
+
+	effectiveStart := s.PartDate(1)
+	interval := time.Duration(s.IntervalDays) * 24 * time.Hour
+
+	part := 1
+	if start.After(effectiveStart) {
+		part = int(start.Sub(effectiveStart)/interval) + 1
+	}
+
+	for {
+		if s.NumParts > 0 && part > s.NumParts {
+			break
+		}
+
+		date := s.PartDate(part)
+		if !date.Before(end) {
+			break
+		}
+
+		if !date.Before(start) {
+			res = append(res, Release{
+				Part: part,
+				Date: date,
+			})
+		}
+
+		part++
+	}
+
+	return res
+}
+
+// CheckReleases creates a notification for every tracked collection that
+// got a new part released since the last check
+func CheckReleases(ctx context.Context, app App) error {
+	collections, err := app.DB().GetCollectionsWithRelease(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		schedule, ok := GetReleaseSchedule(collection)
+		if !ok {
+			continue
+		}
+
+		current := schedule.CurrentPart()
+		if current <= collection.ReleaseNotifiedPart {
+			continue
+		}
+
+		_, err := CreateNotification(ctx, app, NotificationParams{
+			Type:         types.NotificationTypePartRelease,
+			Title:        "New part released",
+			Message:      fmt.Sprintf("Part %d of '%s' has been released", current, collection.Title),
+			CollectionId: collection.Id,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = app.DB().UpdateCollection(ctx, collection.Id, database.CollectionChanges{
+			ReleaseNotifiedPart: database.Change[int]{
+				Value:   current,
+				Changed: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	schedulerTickInterval = 30 * time.Second
+	taskRunsToKeep        = 20
+
+	// NOTE(patrik): Used inside the config to disable a task
+	ScheduleDisabled = "off"
+)
+
+var ErrTaskNotFound = errors.New("core: task not found")
+var ErrTaskRunning = errors.New("core: task is already running")
+
+type TaskFunc func(ctx context.Context, app App) error
+
+type Task struct {
+	Name            string
+	Description     string
+	DefaultSchedule string
+}
+
+type TaskInfo struct {
+	Task
+
+	Schedule string
+	NextRun  *time.Time
+	Running  bool
+}
+
+type scheduledTask struct {
+	Task
+
+	fn TaskFunc
+
+	spec     string
+	schedule cron.Schedule
+	next     time.Time
+
+	running atomic.Bool
+}
+
+// Scheduler runs the registered maintenance tasks on cron like schedules,
+// the schedules can be overridden with the 'schedules' table inside the
+// config file
+type Scheduler struct {
+	app App
+
+	mu      sync.Mutex
+	tasks   []*scheduledTask
+	started bool
+}
+
+func NewScheduler(app App) *Scheduler {
+	return &Scheduler{
+		app: app,
+	}
+}
+
+func (s *Scheduler) Register(task Task, fn TaskFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tasks = append(s.tasks, &scheduledTask{
+		Task: task,
+		fn:   fn,
+		spec: task.DefaultSchedule,
+	})
+}
+
+func (s *Scheduler) findTask(name string) (*scheduledTask, bool) {
+	for _, t := range s.tasks {
+		if t.Name == name {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// Start parses the schedules and starts the scheduler loop
+func (s *Scheduler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return nil
+	}
+
+	overrides := s.app.Config().Schedules
+	for name := range overrides {
+		if _, exists := s.findTask(name); !exists {
+			return fmt.Errorf("schedules: unknown task '%s'", name)
+		}
+	}
+
+	now := time.Now()
+
+	for _, t := range s.tasks {
+		if spec, exists := overrides[t.Name]; exists {
+			t.spec = spec
+		}
+
+		if t.spec == "" || t.spec == ScheduleDisabled {
+			t.spec = ScheduleDisabled
+			continue
+		}
+
+		schedule, err := cron.ParseStandard(t.spec)
+		if err != nil {
+			return fmt.Errorf("schedules: invalid schedule for '%s': %w", t.Name, err)
+		}
+
+		t.schedule = schedule
+		t.next = schedule.Next(now)
+	}
+
+	err := s.app.DB().FailRunningTaskRuns(context.Background())
+	if err != nil {
+		return err
+	}
+
+	go s.loop()
+
+	s.started = true
+
+	return nil
+}
+
+func (s *Scheduler) loop() {
+	ticker := time.NewTicker(schedulerTickInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		s.mu.Lock()
+		for _, t := range s.tasks {
+			if t.schedule == nil || now.Before(t.next) {
+				continue
+			}
+
+			t.next = t.schedule.Next(now)
+
+			if t.running.Load() {
+				s.app.Logger().Warn("Skipping scheduled task, still running", "task", t.Name)
+				continue
+			}
+
+			go s.run(t)
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *Scheduler) run(t *scheduledTask) error {
+	if !t.running.CompareAndSwap(false, true) {
+		return ErrTaskRunning
+	}
+	defer t.running.Store(false)
+
+	ctx := context.Background()
+	db := s.app.DB()
+
+	runId, err := db.CreateTaskRun(ctx, database.CreateTaskRunParams{
+		Task:   t.Name,
+		Status: types.JobStatusRunning,
+	})
+	if err != nil {
+		s.app.Logger().Error("Failed to record task run", "task", t.Name, "err", err)
+		return err
+	}
+
+	s.app.Logger().Info("Running task", "task", t.Name)
+
+	taskErr := t.fn(ctx, s.app)
+
+	status := types.JobStatusSuccess
+	errStr := sql.NullString{}
+	if taskErr != nil {
+		s.app.Logger().Error("Task failed", "task", t.Name, "err", taskErr)
+
+		status = types.JobStatusFailed
+		errStr = sql.NullString{
+			String: taskErr.Error(),
+			Valid:  true,
+		}
+	}
+
+	err = db.UpdateTaskRun(ctx, runId, database.TaskRunChanges{
+		Status: database.Change[types.JobStatus]{
+			Value:   status,
+			Changed: true,
+		},
+		Error: database.Change[sql.NullString]{
+			Value:   errStr,
+			Changed: true,
+		},
+		Finished: database.Change[sql.NullInt64]{
+			Value: sql.NullInt64{
+				Int64: time.Now().UnixMilli(),
+				Valid: true,
+			},
+			Changed: true,
+		},
+	})
+	if err != nil {
+		s.app.Logger().Error("Failed to record task run", "task", t.Name, "err", err)
+	}
+
+	err = db.TrimTaskRuns(ctx, t.Name, taskRunsToKeep)
+	if err != nil {
+		s.app.Logger().Error("Failed to trim task runs", "task", t.Name, "err", err)
+	}
+
+	return taskErr
+}
+
+// Trigger starts the task in the background outside of its schedule
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	t, exists := s.findTask(name)
+	s.mu.Unlock()
+
+	if !exists {
+		return ErrTaskNotFound
+	}
+
+	if t.running.Load() {
+		return ErrTaskRunning
+	}
+
+	go s.run(t)
+
+	return nil
+}
+
+func (s *Scheduler) Tasks() []TaskInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]TaskInfo, len(s.tasks))
+	for i, t := range s.tasks {
+		info := TaskInfo{
+			Task:     t.Task,
+			Schedule: t.spec,
+			Running:  t.running.Load(),
+		}
+
+		if t.schedule != nil {
+			next := t.next
+			info.NextRun = &next
+		}
+
+		res[i] = info
+	}
+
+	return res
+}
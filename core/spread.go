@@ -0,0 +1,369 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/types"
+)
+
+const JobTypeSplitSpreads = "split-spreads"
+
+type SplitSpreadsPayload struct {
+	CollectionId string `json:"collectionId"`
+}
+
+var (
+	ErrImageNotSpread    = errors.New("image is not a spread")
+	ErrImageAlreadySplit = errors.New("image is already split")
+	ErrSpreadPagesExists = errors.New("pages of the spread already exists in the collection")
+)
+
+// IsSpread reports if the image looks like a two-page spread, scanned
+// spreads are stored as a single landscape image
+func IsSpread(img database.Image) bool {
+	if !img.Width.Valid || !img.Height.Valid {
+		return false
+	}
+
+	return img.Width.Int64 > img.Height.Int64
+}
+
+// ReadingPages returns the images in reading order, spreads that has been
+// split are replaced by their pages
+func ReadingPages(images []database.Image) []database.Image {
+	res := make([]database.Image, 0, len(images))
+	for _, img := range images {
+		if img.Split.Valid {
+			continue
+		}
+
+		res = append(res, img)
+	}
+
+	return res
+}
+
+// NOTE(patrik): Only spreads that are still whole can be split, pages
+// created from a spread are never treated as spreads themselves
+func canSplit(img database.Image) error {
+	if img.Split.Valid || img.SourceId.Valid {
+		return ErrImageAlreadySplit
+	}
+
+	if !IsSpread(img) {
+		return ErrImageNotSpread
+	}
+
+	return nil
+}
+
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+type spreadPage struct {
+	side types.SpreadSide
+	data []byte
+}
+
+func encodeSpreadPage(img image.Image, ext string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	switch ext {
+	case ".jpg", ".jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95})
+	default:
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// splitSpreadImage cuts the spread in the middle and returns the pages in
+// reading order
+func splitSpreadImage(src image.Image, ext string, direction types.ReadingDirection) ([]spreadPage, error) {
+	sub, ok := src.(subImager)
+	if !ok {
+		return nil, errors.New("image doesn't support cropping")
+	}
+
+	bounds := src.Bounds()
+	mid := bounds.Min.X + bounds.Dx()/2
+
+	left, err := encodeSpreadPage(sub.SubImage(image.Rect(bounds.Min.X, bounds.Min.Y, mid, bounds.Max.Y)), ext)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := encodeSpreadPage(sub.SubImage(image.Rect(mid, bounds.Min.Y, bounds.Max.X, bounds.Max.Y)), ext)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := []spreadPage{
+		{side: types.SpreadSideLeft, data: left},
+		{side: types.SpreadSideRight, data: right},
+	}
+
+	if direction == types.ReadingDirectionRTL {
+		pages[0], pages[1] = pages[1], pages[0]
+	}
+
+	return pages, nil
+}
+
+// SplitSpread splits a spread into two new pages placed directly after
+// the spread, the spread itself is kept and marked as split. The order of
+// the pages follows the reading direction of the collection
+func SplitSpread(ctx context.Context, app App, dbImage database.Image) ([]string, error) {
+	err := canSplit(dbImage)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := app.DB().GetCollectionById(ctx, dbImage.CollectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := decodeStoredImage(ctx, app, dbImage)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(path.Ext(dbImage.Filename))
+	if ext != ".jpg" && ext != ".jpeg" {
+		ext = ".png"
+	}
+
+	reader, err := GetCollectionReaderSettings(ctx, app, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := splitSpreadImage(src, ext, reader.Direction)
+	if err != nil {
+		return nil, err
+	}
+
+	alg := app.Config().HashAlgorithm
+
+	params := make([]database.CreateImageParams, len(pages))
+	for i, page := range pages {
+		hash, err := StoreBlob(ctx, app, page.data, alg)
+		if err != nil {
+			return nil, err
+		}
+
+		analysis, err := AnalyzeImage(page.data)
+		if err != nil {
+			return nil, err
+		}
+
+		params[i] = database.CreateImageParams{
+			CollectionId:  dbImage.CollectionId,
+			Hash:          hash,
+			HashAlgorithm: alg,
+			PHash:         analysis.PHash,
+			Width:         analysis.Width,
+			Height:        analysis.Height,
+			Size:          analysis.Size,
+			MimeType:      analysis.MimeType,
+			ColorMode:     analysis.ColorMode,
+			Animated:      analysis.Animated,
+			SourceId: sql.NullString{
+				String: dbImage.Id,
+				Valid:  true,
+			},
+			SpreadSide: sql.NullString{
+				String: string(page.side),
+				Valid:  true,
+			},
+			Filename: hash + ext,
+		}
+	}
+
+	tx, err := app.DB().Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// NOTE(patrik): Refetch the image inside the transaction so the
+	// position is up to date and the spread isn't split twice
+	dbImage, err = tx.GetImageById(ctx, dbImage.CollectionId, dbImage.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = canSplit(dbImage)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE(patrik): A collection can only contain an image once, a blank
+	// half is often already in the collection so that half is skipped
+	var create []database.CreateImageParams
+	for _, p := range params {
+		_, err := tx.GetImageByHash(ctx, p.CollectionId, p.Hash)
+		if err == nil {
+			app.Logger().Warn("Skipping duplicated spread page", "imageId", dbImage.Id, "side", p.SpreadSide.String, "collectionId", dbImage.CollectionId)
+			continue
+		}
+
+		if !errors.Is(err, database.ErrItemNotFound) {
+			return nil, err
+		}
+
+		create = append(create, p)
+	}
+
+	if len(create) == 0 {
+		return nil, ErrSpreadPagesExists
+	}
+
+	next, err := tx.GetNextImagePosition(ctx, dbImage.CollectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.ShiftImagePositions(ctx, dbImage.CollectionId, dbImage.Position+1, next, len(create))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(create))
+	for i, p := range create {
+		p.Position = dbImage.Position + 1 + i
+
+		ids[i], err = tx.CreateImage(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.UpdateImage(ctx, dbImage.CollectionId, dbImage.Id, database.ImageChanges{
+		Split: database.Change[sql.NullInt64]{
+			Value: sql.NullInt64{
+				Int64: time.Now().UnixMilli(),
+				Valid: true,
+			},
+			Changed: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// undoSplitTx removes the pages created from the spread and clears split
+// on it, the removed pages are returned so the caller can remove the blobs
+// after the commit
+func undoSplitTx(ctx context.Context, tx database.Tx, collectionId, spreadId string) ([]database.Image, error) {
+	// NOTE(patrik): Sorted by position in descending order so the shifts
+	// doesn't move the pages not yet removed
+	pages, err := tx.GetImagesBySourceId(ctx, collectionId, spreadId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, page := range pages {
+		err := removeImageFromTx(ctx, tx, page)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.UpdateImage(ctx, collectionId, spreadId, database.ImageChanges{
+		Split: database.Change[sql.NullInt64]{
+			Value:   sql.NullInt64{},
+			Changed: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+func splitSpreadsJob(job *JobContext) error {
+	var payload SplitSpreadsPayload
+	err := job.Payload(&payload)
+	if err != nil {
+		return err
+	}
+
+	app := job.App()
+
+	images, err := app.DB().GetAllImagesByCollectionId(job, payload.CollectionId)
+	if err != nil {
+		return err
+	}
+
+	var spreads []database.Image
+	for _, img := range images {
+		if canSplit(img) == nil {
+			spreads = append(spreads, img)
+		}
+	}
+
+	job.Log("Splitting %d spreads", len(spreads))
+
+	split := 0
+	for i, img := range spreads {
+		if err := job.Err(); err != nil {
+			return err
+		}
+
+		_, err := SplitSpread(job, app, img)
+		if err != nil {
+			job.Log("Failed to split %s: %v", img.Id, err)
+		} else {
+			split++
+		}
+
+		job.SetProgress(i+1, len(spreads))
+	}
+
+	if split == 0 {
+		return nil
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionUpdated,
+		CollectionId: payload.CollectionId,
+	})
+
+	jobId, err := app.Jobs().Enqueue(context.Background(), JobTypeGenerateThumbnails, GenerateThumbnailsPayload{
+		CollectionId: payload.CollectionId,
+	})
+	if err != nil {
+		return err
+	}
+
+	job.Log("Queued thumbnail generation (%s)", jobId)
+
+	return nil
+}
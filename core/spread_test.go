@@ -0,0 +1,269 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/nanoteck137/storebook/database"
+)
+
+func grayImage(w, h int, fill func(x, y int) uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{Y: fill(x, y)})
+		}
+	}
+
+	return img
+}
+
+func addTestImage(t *testing.T, app App, collectionId string, img image.Image, position int) database.Image {
+	t.Helper()
+
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alg := app.Config().HashAlgorithm
+
+	hash, err := StoreBlob(ctx, app, buf.Bytes(), alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := AnalyzeImage(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := app.DB().CreateImage(ctx, database.CreateImageParams{
+		CollectionId:  collectionId,
+		Hash:          hash,
+		HashAlgorithm: alg,
+		PHash:         analysis.PHash,
+		Width:         analysis.Width,
+		Height:        analysis.Height,
+		Size:          analysis.Size,
+		MimeType:      analysis.MimeType,
+		ColorMode:     analysis.ColorMode,
+		Animated:      analysis.Animated,
+		Filename:      hash + ".png",
+		Position:      position,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbImage, err := app.DB().GetImageById(ctx, collectionId, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dbImage
+}
+
+func TestSplitSpreadSkipsExistingPage(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: "Test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE(patrik): The left half of the spread is identical to the blank
+	// page already in the collection
+	blank := addTestImage(t, app, collectionId, grayImage(20, 20, func(x, y int) uint8 { return 255 }), 0)
+	spread := addTestImage(t, app, collectionId, grayImage(40, 20, func(x, y int) uint8 {
+		if x < 20 {
+			return 255
+		}
+
+		return uint8(x * y)
+	}), 1)
+
+	ids, err := SplitSpread(ctx, app, spread)
+	if err != nil {
+		t.Fatalf("failed to split spread: %v", err)
+	}
+
+	if len(ids) != 1 {
+		t.Fatalf("expected a single page, got %d", len(ids))
+	}
+
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := ReadingPages(images)
+	if len(pages) != 2 || pages[0].Id != blank.Id || pages[1].Id != ids[0] {
+		t.Fatalf("unexpected reading pages %+v", pages)
+	}
+
+	for i, img := range images {
+		if img.Position != i {
+			t.Fatalf("image %s has position %d, expected %d", img.Id, img.Position, i)
+		}
+	}
+
+	_, err = SplitSpread(ctx, app, spread)
+	if err == nil {
+		t.Fatal("expected splitting the spread twice to fail")
+	}
+
+	err = RemoveCollectionImage(ctx, app, collectionId, ids[0])
+	if err != nil {
+		t.Fatalf("failed to remove page: %v", err)
+	}
+
+	spread, err = app.DB().GetImageById(ctx, collectionId, spread.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if spread.Split.Valid {
+		t.Fatal("expected the spread to not be split after removing the page")
+	}
+
+	images, err = app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages = ReadingPages(images)
+	if len(pages) != 2 || pages[1].Id != spread.Id || pages[1].Position != 1 {
+		t.Fatalf("unexpected reading pages after removal %+v", pages)
+	}
+}
+
+func TestRemoveSpreadPageRemovesAllPages(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: "Test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spread := addTestImage(t, app, collectionId, grayImage(40, 20, func(x, y int) uint8 { return uint8(x * y) }), 0)
+	last := addTestImage(t, app, collectionId, grayImage(20, 20, func(x, y int) uint8 { return uint8(x + y) }), 1)
+
+	ids, err := SplitSpread(ctx, app, spread)
+	if err != nil {
+		t.Fatalf("failed to split spread: %v", err)
+	}
+
+	if len(ids) != 2 {
+		t.Fatalf("expected two pages, got %d", len(ids))
+	}
+
+	err = RemoveCollectionImage(ctx, app, collectionId, ids[1])
+	if err != nil {
+		t.Fatalf("failed to remove page: %v", err)
+	}
+
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != 2 || images[0].Id != spread.Id || images[1].Id != last.Id {
+		t.Fatalf("unexpected images %+v", images)
+	}
+
+	if images[0].Split.Valid || images[1].Position != 1 {
+		t.Fatalf("unexpected state after removal %+v", images)
+	}
+}
+
+func TestReplaceSplitSpreadUndoesSplit(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: "Test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spread := addTestImage(t, app, collectionId, grayImage(40, 20, func(x, y int) uint8 { return uint8(x * y) }), 0)
+
+	_, err = SplitSpread(ctx, app, spread)
+	if err != nil {
+		t.Fatalf("failed to split spread: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = png.Encode(&buf, grayImage(20, 30, func(x, y int) uint8 { return uint8(x + y) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ReplaceCollectionImage(ctx, app, collectionId, spread.Id, buf.Bytes(), ".png")
+	if err != nil {
+		t.Fatalf("failed to replace image: %v", err)
+	}
+
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != 1 || images[0].Id != spread.Id {
+		t.Fatalf("expected only the replaced image, got %+v", images)
+	}
+
+	if images[0].Split.Valid || images[0].Hash == spread.Hash || images[0].Width.Int64 != 20 {
+		t.Fatalf("image wasn't replaced %+v", images[0])
+	}
+}
+
+func TestRemoveSplitSpreadRemovesPages(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: "Test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := addTestImage(t, app, collectionId, grayImage(20, 20, func(x, y int) uint8 { return uint8(x + y) }), 0)
+	spread := addTestImage(t, app, collectionId, grayImage(40, 20, func(x, y int) uint8 { return uint8(x * y) }), 1)
+	last := addTestImage(t, app, collectionId, grayImage(20, 20, func(x, y int) uint8 { return uint8(x - y) }), 2)
+
+	_, err = SplitSpread(ctx, app, spread)
+	if err != nil {
+		t.Fatalf("failed to split spread: %v", err)
+	}
+
+	err = RemoveCollectionImage(ctx, app, collectionId, spread.Id)
+	if err != nil {
+		t.Fatalf("failed to remove spread: %v", err)
+	}
+
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != 2 || images[0].Id != first.Id || images[1].Id != last.Id || images[1].Position != 1 {
+		t.Fatalf("unexpected images after removal %+v", images)
+	}
+}
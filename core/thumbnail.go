@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"image"
+	"image/jpeg"
+	"os"
+	"path"
+
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/nanoteck137/storebook/database"
+	"golang.org/x/image/draw"
+)
+
+const JobTypeGenerateThumbnails = "generate-thumbnails"
+
+type ThumbnailSize struct {
+	Name  string
+	Width int
+}
+
+var ThumbnailSizes = []ThumbnailSize{
+	{Name: "small", Width: 160},
+	{Name: "medium", Width: 320},
+	{Name: "large", Width: 640},
+}
+
+type GenerateThumbnailsPayload struct {
+	CollectionId string `json:"collectionId"`
+}
+
+func ThumbnailFilename(hash, size string) string {
+	return hash + "." + size + ".jpg"
+}
+
+func decodeStoredImage(ctx context.Context, app App, dbImage database.Image) (image.Image, error) {
+	f, _, err := OpenImage(ctx, app, dbImage)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return img, nil
+}
+
+func writeThumbnail(src image.Image, width int, out string) error {
+	bounds := src.Bounds()
+
+	// NOTE(patrik): Never upscale the image
+	if bounds.Dx() < width {
+		width = bounds.Dx()
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height <= 0 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return jpeg.Encode(f, dst, &jpeg.Options{Quality: 85})
+}
+
+// GenerateThumbnails creates the missing thumbnails for all the images
+// inside the collection
+func GenerateThumbnails(ctx context.Context, app App, collectionId string, progress ProgressFunc) error {
+	images, err := app.DB().GetAllImagesByCollectionId(ctx, collectionId)
+	if err != nil {
+		return err
+	}
+
+	collectionDir := app.WorkDir().CollectionDirById(collectionId)
+	err = collectionDir.Create()
+	if err != nil {
+		return err
+	}
+
+	for i, img := range images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var src image.Image
+
+		for _, size := range ThumbnailSizes {
+			out := path.Join(collectionDir.Thumbnails(), ThumbnailFilename(img.Hash, size.Name))
+
+			_, err := os.Stat(out)
+			if err == nil {
+				continue
+			}
+
+			if src == nil {
+				src, err = decodeStoredImage(ctx, app, img)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = writeThumbnail(src, size.Width, out)
+			if err != nil {
+				return err
+			}
+		}
+
+		if progress != nil {
+			progress(i+1, len(images))
+		}
+	}
+
+	return nil
+}
+
+func generateThumbnailsJob(job *JobContext) error {
+	var payload GenerateThumbnailsPayload
+	err := job.Payload(&payload)
+	if err != nil {
+		return err
+	}
+
+	return GenerateThumbnails(job, job.App(), payload.CollectionId, job.SetProgress)
+}
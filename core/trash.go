@@ -0,0 +1,74 @@
+package core
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// PurgeCollection removes the collection from the database together with
+// all the files only used by it
+func PurgeCollection(ctx context.Context, app App, id string) error {
+	err := app.DB().RemoveCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	dir := app.WorkDir().CollectionDirById(id)
+	err = os.RemoveAll(dir.String())
+	if err != nil {
+		return err
+	}
+
+	// NOTE(patrik): Removing the collection drops the references to the
+	// blobs, blobs shared with other collections are kept
+	_, err = RemoveUnreferencedBlobs(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// discardCollection purges a collection that was created for an import
+// that failed
+func discardCollection(ctx context.Context, app App, id string) error {
+	err := PurgeCollection(ctx, app, id)
+	if err != nil {
+		return err
+	}
+
+	app.Broker().EmitEvent(CollectionEvent{
+		Type:         EventCollectionDeleted,
+		CollectionId: id,
+	})
+
+	return nil
+}
+
+// PurgeTrash purges all the collections that have been in the trash for
+// longer then the configured retention period
+func PurgeTrash(ctx context.Context, app App) error {
+	days := app.Config().TrashRetentionDays
+	if days <= 0 {
+		return nil
+	}
+
+	t := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+
+	collections, err := app.DB().GetCollectionsDeletedBefore(ctx, t.UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		app.Logger().Info("Purging collection from trash", "id", collection.Id, "title", collection.Title)
+
+		err := PurgeCollection(ctx, app, collection.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
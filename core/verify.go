@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/storage"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+const JobTypeVerifyImages = "verify-images"
+
+type VerifyImagesPayload struct {
+	// NOTE(patrik): Re-verify images that have already been verified
+	Full bool `json:"full"`
+}
+
+type VerifyIssueType string
+
+const (
+	VerifyIssueMismatch   VerifyIssueType = "mismatch"
+	VerifyIssueMissing    VerifyIssueType = "missing"
+	VerifyIssueUnreadable VerifyIssueType = "unreadable"
+)
+
+type VerifyIssue struct {
+	Type VerifyIssueType `json:"type"`
+
+	CollectionId string `json:"collectionId"`
+	ImageId      string `json:"imageId"`
+	Filename     string `json:"filename"`
+
+	// NOTE(patrik): The hash of the file on disk for mismatches or the
+	// error for unreadable files
+	Detail string `json:"detail,omitempty"`
+}
+
+type VerifyReport struct {
+	Checked int           `json:"checked"`
+	Issues  []VerifyIssue `json:"issues"`
+}
+
+// VerifyImages re-hashes the stored image files and compares them with the
+// hash inside the database, unless full is set only the images that have
+// never been verified or have changed since the last run are checked
+func VerifyImages(ctx context.Context, app App, full bool, progress ProgressFunc) (VerifyReport, error) {
+	report := VerifyReport{
+		Issues: []VerifyIssue{},
+	}
+
+	db := app.DB()
+	start := time.Now().UnixMilli()
+
+	images, err := db.GetImagesToVerify(ctx, full)
+	if err != nil {
+		return report, err
+	}
+
+	for i, img := range images {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		issue := VerifyIssue{
+			CollectionId: img.CollectionId,
+			ImageId:      img.Id,
+			Filename:     img.Filename,
+		}
+
+		hash, err := hashStoredImage(ctx, app, img, img.HashAlgorithm)
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			issue.Type = VerifyIssueMissing
+		case err != nil:
+			issue.Type = VerifyIssueUnreadable
+			issue.Detail = err.Error()
+		case hash != img.Hash:
+			issue.Type = VerifyIssueMismatch
+			issue.Detail = hash
+		}
+
+		report.Checked++
+
+		// NOTE(patrik): Only mark the good images as verified so the bad
+		// ones gets reported again on the next run
+		if issue.Type != "" {
+			report.Issues = append(report.Issues, issue)
+		} else {
+			err := db.MarkImageVerified(ctx, img.CollectionId, img.Id, start)
+			if err != nil {
+				return report, err
+			}
+		}
+
+		if progress != nil {
+			progress(i+1, len(images))
+		}
+	}
+
+	return report, nil
+}
+
+func hashStoredImage(ctx context.Context, app App, img database.Image, alg types.HashAlgorithm) (string, error) {
+	r, _, err := OpenImage(ctx, app, img)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	return utils.HashReader(r, alg)
+}
+
+func verifyImagesJob(job *JobContext) error {
+	var payload VerifyImagesPayload
+	err := job.Payload(&payload)
+	if err != nil {
+		return err
+	}
+
+	app := job.App()
+
+	report, err := VerifyImages(job, app, payload.Full, job.SetProgress)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range report.Issues {
+		job.Log("%s: %s/%s (%s) %s", issue.Type, issue.CollectionId, issue.ImageId, issue.Filename, issue.Detail)
+	}
+
+	err = job.SetResult(report)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Issues) > 0 {
+		_, err = CreateNotification(context.Background(), app, NotificationParams{
+			Type:    types.NotificationTypeGeneric,
+			Title:   "Verification found problems",
+			Message: fmt.Sprintf("%d of %d checked images failed verification", len(report.Issues), report.Checked),
+			JobId:   job.Id(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NOTE(patrik): Runs as a job so the progress and the found issues shows
+// up the same way as a manually started verification
+func verifyTask(ctx context.Context, app App) error {
+	_, err := app.Jobs().Enqueue(ctx, JobTypeVerifyImages, VerifyImagesPayload{
+		Full: true,
+	})
+
+	return err
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nanoteck137/storebook/config"
+	"github.com/nanoteck137/storebook/database"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+// NOTE(patrik): The directories are polled instead of using inotify
+// because the events are not delivered for network mounts
+type watchedFile struct {
+	size    int64
+	modTime time.Time
+}
+
+type watchImporter struct {
+	mu   sync.Mutex
+	seen map[string]watchedFile
+}
+
+func newWatchImporter() *watchImporter {
+	return &watchImporter{
+		seen: make(map[string]watchedFile),
+	}
+}
+
+// TitleFromFilename turns the filename of an archive into a collection
+// title, "My_Series_v01.cbz" becomes "My Series v01"
+func TitleFromFilename(name string) string {
+	title := strings.TrimSuffix(name, path.Ext(name))
+	title = utils.FixSpaces(strings.ReplaceAll(title, "_", " "))
+
+	if title == "" {
+		return name
+	}
+
+	return title
+}
+
+// NOTE(patrik): An archive needs to have the same size and modification
+// time on two runs in a row before it's imported so that archives still
+// being copied into the directory are left alone
+func (w *watchImporter) run(ctx context.Context, app App) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := make(map[string]watchedFile)
+
+	var errs []error
+	for _, watch := range app.Config().Watch {
+		err := w.check(ctx, app, watch, seen)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("watch '%s': %w", watch.Dir, err))
+		}
+	}
+
+	w.seen = seen
+
+	return errors.Join(errs...)
+}
+
+func (w *watchImporter) check(ctx context.Context, app App, watch config.WatchConfig, seen map[string]watchedFile) error {
+	entries, err := os.ReadDir(watch.Dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !IsArchiveExt(path.Ext(name)) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			// NOTE(patrik): The file was moved away after the listing
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		p := path.Join(watch.Dir, name)
+
+		current := watchedFile{
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+
+		prev, exists := w.seen[p]
+		if !exists || prev.size != current.size || !prev.modTime.Equal(current.modTime) {
+			seen[p] = current
+			continue
+		}
+
+		err = importWatchedArchive(ctx, app, watch, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// importWatchedArchive moves the archive into the uploads dir and queues
+// the same import job as the uploads, the job moves the archive into the
+// processed or failed directory when it's done
+func importWatchedArchive(ctx context.Context, app App, watch config.WatchConfig, name string) error {
+	processedDir := watch.ProcessedDir
+	if processedDir == "" {
+		processedDir = path.Join(watch.Dir, "processed")
+	}
+
+	failedDir := watch.FailedDir
+	if failedDir == "" {
+		failedDir = path.Join(watch.Dir, "failed")
+	}
+
+	out := path.Join(app.WorkDir().UploadsDir(), utils.CreateId()+strings.ToLower(path.Ext(name)))
+	err := utils.MoveFile(path.Join(watch.Dir, name), out)
+	if err != nil {
+		return err
+	}
+
+	collectionId, err := CreateCollection(ctx, app, database.CreateCollectionParams{
+		Title: TitleFromFilename(name),
+	})
+	if err != nil {
+		moveErr := moveArchive(out, failedDir, name)
+		return errors.Join(err, moveErr)
+	}
+
+	jobId, err := app.Jobs().Enqueue(ctx, JobTypeImportArchive, ImportArchivePayload{
+		CollectionId: collectionId,
+		File:         out,
+		Name:         name,
+		ProcessedDir: processedDir,
+		FailedDir:    failedDir,
+	})
+	if err != nil {
+		purgeErr := discardCollection(ctx, app, collectionId)
+		moveErr := moveArchive(out, failedDir, name)
+		return errors.Join(err, purgeErr, moveErr)
+	}
+
+	app.Logger().Info("Queued import of watched archive", "file", name, "collectionId", collectionId, "jobId", jobId)
+
+	return nil
+}
+
+// moveArchive moves the archive into the directory under the name, a
+// number is added to the name if the directory already has a file with
+// the same name
+func moveArchive(src, dir, name string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	dst := path.Join(dir, name)
+	for i := 1; ; i++ {
+		_, err := os.Stat(dst)
+		if os.IsNotExist(err) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		dst = path.Join(dir, fmt.Sprintf("%s (%d)%s", base, i, ext))
+	}
+
+	return utils.MoveFile(src, dst)
+}
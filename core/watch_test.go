@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/nanoteck137/storebook/config"
+)
+
+func TestWatchFailedImportRemovesCollection(t *testing.T) {
+	app := newTestApp(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	app.Config().Watch = []config.WatchConfig{{Dir: dir}}
+
+	err := os.WriteFile(path.Join(dir, "Broken v01.cbz"), []byte("not a zip"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE(patrik): The first run only records the file, the second run
+	// imports it when the file hasn't changed
+	w := newWatchImporter()
+	for range 2 {
+		err := w.run(ctx, app)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = app.Jobs().Start(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := path.Join(dir, "failed", "Broken v01.cbz")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := os.Stat(failed)
+		if err == nil {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("archive was never moved into the failed directory")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ids, err := app.DB().GetAllCollectionIds(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 0 {
+		t.Fatalf("expected the collection to be removed, got %v", ids)
+	}
+
+	_, err = os.Stat(path.Join(dir, "Broken v01.cbz"))
+	if !os.IsNotExist(err) {
+		t.Fatal("expected the archive to be moved out of the watch directory")
+	}
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Backup writes a consistent snapshot of the database to the file with
+// the SQLite online backup API, safe to use while the server is running
+func (db *Database) Backup(ctx context.Context, dst string) error {
+	srcConn, err := db.db.DB.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	dstDB, err := sql.Open("sqlite3", dst)
+	if err != nil {
+		return err
+	}
+	defer dstDB.Close()
+
+	dstConn, err := dstDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer dstConn.Close()
+
+	return dstConn.Raw(func(dstDriverConn any) error {
+		return srcConn.Raw(func(srcDriverConn any) error {
+			dst, ok := dstDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("database: unexpected driver connection")
+			}
+
+			src, ok := srcDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return errors.New("database: unexpected driver connection")
+			}
+
+			backup, err := dst.Backup("main", src, "main")
+			if err != nil {
+				return err
+			}
+
+			// NOTE(patrik): Copy all the pages in a single step, the
+			// backup restarts if the database is written to between
+			// steps
+			_, err = backup.Step(-1)
+			if err != nil {
+				backup.Finish()
+				return err
+			}
+
+			return backup.Finish()
+		})
+	})
+}
+
+// CheckIntegrity runs the SQLite integrity check on the database
+func (db *Database) CheckIntegrity(ctx context.Context) error {
+	var res string
+	err := db.db.DB.DB.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&res)
+	if err != nil {
+		return err
+	}
+
+	if res != "ok" {
+		return fmt.Errorf("database: integrity check failed: %s", res)
+	}
+
+	return nil
+}
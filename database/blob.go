@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/nanoteck137/pyrin/ember"
+	"github.com/nanoteck137/storebook/types"
+)
+
+// NOTE(patrik): The ref_count column is maintained by triggers on the
+// images table
+type Blob struct {
+	Hash          string              `db:"hash"`
+	HashAlgorithm types.HashAlgorithm `db:"hash_algorithm"`
+
+	Size     int64 `db:"size"`
+	RefCount int   `db:"ref_count"`
+
+	Created int64 `db:"created"`
+	Updated int64 `db:"updated"`
+}
+
+// TODO(patrik): Use goqu.T more
+func BlobQuery() *goqu.SelectDataset {
+	query := dialect.From("blobs").
+		Select(
+			"blobs.hash",
+			"blobs.hash_algorithm",
+
+			"blobs.size",
+			"blobs.ref_count",
+
+			"blobs.created",
+			"blobs.updated",
+		)
+
+	return query
+}
+
+func (db DB) GetAllBlobs(ctx context.Context) ([]Blob, error) {
+	query := BlobQuery()
+	return ember.Multiple[Blob](db.db, ctx, query)
+}
+
+func (db DB) GetBlob(ctx context.Context, alg types.HashAlgorithm, hash string) (Blob, error) {
+	query := BlobQuery().
+		Where(
+			goqu.I("blobs.hash_algorithm").Eq(alg),
+			goqu.I("blobs.hash").Eq(hash),
+		)
+
+	return ember.Single[Blob](db.db, ctx, query)
+}
+
+// NOTE(patrik): Returns the blobs without any references that haven't been
+// touched since before the given time
+func (db DB) GetUnreferencedBlobs(ctx context.Context, before int64) ([]Blob, error) {
+	query := BlobQuery().
+		Where(
+			goqu.I("blobs.ref_count").Lte(0),
+			goqu.I("blobs.updated").Lt(before),
+		)
+
+	return ember.Multiple[Blob](db.db, ctx, query)
+}
+
+type CreateBlobParams struct {
+	Hash          string
+	HashAlgorithm types.HashAlgorithm
+
+	Size int64
+
+	Created int64
+	Updated int64
+}
+
+// NOTE(patrik): Creates the blob or touches the updated column if the blob
+// already exists so it isn't removed before the new reference is added
+func (db DB) CreateOrTouchBlob(ctx context.Context, params CreateBlobParams) error {
+	t := time.Now().UnixMilli()
+	created := params.Created
+	updated := params.Updated
+
+	if created == 0 && updated == 0 {
+		created = t
+		updated = t
+	}
+
+	query := dialect.Insert("blobs").Rows(goqu.Record{
+		"hash":           params.Hash,
+		"hash_algorithm": params.HashAlgorithm,
+
+		"size": params.Size,
+
+		"created": created,
+		"updated": updated,
+	}).
+		OnConflict(goqu.DoUpdate("hash_algorithm, hash", goqu.Record{
+			"size":    params.Size,
+			"updated": updated,
+		}))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NOTE(patrik): Only removes the blob if nothing references it
+func (db DB) RemoveUnreferencedBlob(ctx context.Context, alg types.HashAlgorithm, hash string) (bool, error) {
+	query := dialect.Delete("blobs").
+		Where(
+			goqu.I("blobs.hash_algorithm").Eq(alg),
+			goqu.I("blobs.hash").Eq(hash),
+			goqu.I("blobs.ref_count").Lte(0),
+		)
+
+	res, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+type BlobStats struct {
+	Blobs      int   `db:"blobs"`
+	References int   `db:"refs"`
+	StoredSize int64 `db:"stored_size"`
+	// NOTE(patrik): The size it would take without deduplication
+	LogicalSize int64 `db:"logical_size"`
+}
+
+func (db DB) GetBlobStats(ctx context.Context) (BlobStats, error) {
+	query := dialect.From("blobs").
+		Select(
+			goqu.COUNT("*").As("blobs"),
+			goqu.L("COALESCE(SUM(?), 0)", goqu.I("blobs.ref_count")).As("refs"),
+			goqu.L("COALESCE(SUM(?), 0)", goqu.I("blobs.size")).As("stored_size"),
+			goqu.L("COALESCE(SUM(? * ?), 0)", goqu.I("blobs.size"), goqu.I("blobs.ref_count")).As("logical_size"),
+		).
+		Where(goqu.I("blobs.ref_count").Gt(0))
+
+	return ember.Single[BlobStats](db.db, ctx, query)
+}
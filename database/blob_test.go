@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nanoteck137/storebook/types"
+)
+
+func expectRefCount(t *testing.T, db *Database, hash string, expected int) {
+	t.Helper()
+
+	blob, err := db.GetBlob(context.Background(), types.HashAlgorithmSHA256, hash)
+	if err != nil {
+		t.Fatalf("failed to get blob %s: %v", hash, err)
+	}
+
+	if blob.RefCount != expected {
+		t.Fatalf("blob %s has ref_count %d, expected %d", hash, blob.RefCount, expected)
+	}
+}
+
+func TestBlobRefCountTriggers(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	for _, hash := range []string{"aaaa", "bbbb"} {
+		err := db.CreateOrTouchBlob(ctx, CreateBlobParams{
+			Hash:          hash,
+			HashAlgorithm: types.HashAlgorithmSHA256,
+			Size:          10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	createImage := func(collectionId, hash string, position int) string {
+		id, err := db.CreateImage(ctx, CreateImageParams{
+			CollectionId:  collectionId,
+			Hash:          hash,
+			HashAlgorithm: types.HashAlgorithmSHA256,
+			Filename:      hash + ".png",
+			Position:      position,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return id
+	}
+
+	first, err := db.CreateCollection(ctx, CreateCollectionParams{Title: "First"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := db.CreateCollection(ctx, CreateCollectionParams{Title: "Second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE(patrik): The same content inside two collections shares the blob
+	imageId := createImage(first, "aaaa", 0)
+	createImage(second, "aaaa", 0)
+	expectRefCount(t, db, "aaaa", 2)
+	expectRefCount(t, db, "bbbb", 0)
+
+	err = db.UpdateImage(ctx, first, imageId, ImageChanges{
+		Hash: Change[string]{Value: "bbbb", Changed: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRefCount(t, db, "aaaa", 1)
+	expectRefCount(t, db, "bbbb", 1)
+
+	err = db.RemoveImage(ctx, first, imageId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRefCount(t, db, "bbbb", 0)
+
+	// NOTE(patrik): The images are removed with the collection
+	err = db.RemoveCollection(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectRefCount(t, db, "aaaa", 0)
+
+	blobs, err := db.GetUnreferencedBlobs(ctx, time.Now().Add(time.Minute).UnixMilli())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(blobs) != 2 {
+		t.Fatalf("expected 2 unreferenced blobs, got %d", len(blobs))
+	}
+
+	removed, err := db.RemoveUnreferencedBlob(ctx, types.HashAlgorithmSHA256, "aaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !removed {
+		t.Fatal("expected the unreferenced blob to be removed")
+	}
+}
+
+func TestRemoveUnreferencedBlobKeepsReferenced(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	err := db.CreateOrTouchBlob(ctx, CreateBlobParams{
+		Hash:          "aaaa",
+		HashAlgorithm: types.HashAlgorithmSHA256,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collectionId, err := db.CreateCollection(ctx, CreateCollectionParams{Title: "Test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.CreateImage(ctx, CreateImageParams{
+		CollectionId:  collectionId,
+		Hash:          "aaaa",
+		HashAlgorithm: types.HashAlgorithmSHA256,
+		Filename:      "aaaa.png",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := db.RemoveUnreferencedBlob(ctx, types.HashAlgorithmSHA256, "aaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if removed {
+		t.Fatal("removed a blob that is still referenced")
+	}
+
+	expectRefCount(t, db, "aaaa", 1)
+}
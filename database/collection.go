@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
@@ -17,6 +19,38 @@ type Collection struct {
 
 	Title string `db:"title"`
 
+	// NOTE(patrik): Set by the bulk import from the directory structure
+	Series sql.NullString `db:"series"`
+	Volume sql.NullInt64  `db:"volume"`
+
+	SourceHash sql.NullString `db:"source_hash"`
+
+	Deleted sql.NullInt64 `db:"deleted"`
+
+	ReleaseStart        sql.NullString `db:"release_start"`
+	ReleaseDelayDays    int            `db:"release_delay_days"`
+	ReleaseIntervalDays int            `db:"release_interval_days"`
+	ReleaseNumParts     int            `db:"release_num_parts"`
+	ReleaseNotifiedPart int            `db:"release_notified_part"`
+
+	// NOTE(patrik): Overrides for the reader defaults, null when the
+	// collection uses the default
+	ReaderDirection sql.NullString `db:"reader_direction"`
+	ReaderLayout    sql.NullString `db:"reader_layout"`
+	ReaderFit       sql.NullString `db:"reader_fit"`
+
+	// NOTE(patrik): CoverImageId is the image picked as the cover and
+	// CoverHash is set when a custom cover has been uploaded
+	CoverImageId       sql.NullString `db:"cover_image_id"`
+	CoverHash          sql.NullString `db:"cover_hash"`
+	CoverHashAlgorithm sql.NullString `db:"cover_hash_algorithm"`
+	CoverFilename      sql.NullString `db:"cover_filename"`
+
+	// NOTE(patrik): The image used as the cover when there is no custom
+	// cover, the picked image or the first page
+	CoverImageHash     sql.NullString `db:"cover_image_hash"`
+	CoverImageFilename sql.NullString `db:"cover_image_filename"`
+
 	Created int64 `db:"created"`
 	Updated int64 `db:"updated"`
 }
@@ -31,8 +65,51 @@ func CollectionQuery() *goqu.SelectDataset {
 
 			"collections.title",
 
+			"collections.series",
+			"collections.volume",
+
+			"collections.source_hash",
+
+			"collections.deleted",
+
+			"collections.release_start",
+			"collections.release_delay_days",
+			"collections.release_interval_days",
+			"collections.release_num_parts",
+			"collections.release_notified_part",
+
+			"collections.reader_direction",
+			"collections.reader_layout",
+			"collections.reader_fit",
+
+			"collections.cover_image_id",
+			"collections.cover_hash",
+			"collections.cover_hash_algorithm",
+			"collections.cover_filename",
+
+			goqu.I("cover.hash").As("cover_image_hash"),
+			goqu.I("cover.filename").As("cover_image_filename"),
+
 			"collections.created",
 			"collections.updated",
+		).
+		// NOTE(patrik): Prefer the picked image and fallback to the first
+		// page, spreads that has been split are skipped
+		LeftJoin(
+			goqu.T("images").As("cover"),
+			goqu.On(goqu.L(`cover.rowid = COALESCE(
+				(
+					SELECT images.rowid FROM images
+					WHERE images.collection_id = collections.id
+					AND images.id = collections.cover_image_id
+				),
+				(
+					SELECT images.rowid FROM images
+					WHERE images.collection_id = collections.id
+					ORDER BY images.split IS NOT NULL, images.position
+					LIMIT 1
+				)
+			)`)),
 		)
 
 	return query
@@ -44,9 +121,35 @@ type FetchOptions struct {
 	Page    int
 }
 
-func (db DB) GetPagedCollection(ctx context.Context, opts FetchOptions) ([]Collection, types.Page, error) {
-	query := CollectionQuery()
+type CollectionFilter struct {
+	// NOTE(patrik): Every word inside the query needs to be part of the
+	// title, the match is case insensitive
+	Query string
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (db DB) GetPagedCollection(ctx context.Context, filter CollectionFilter, opts FetchOptions) ([]Collection, types.Page, error) {
+	query := CollectionQuery().
+		Where(goqu.I("collections.deleted").IsNull())
+
+	for _, word := range strings.Fields(filter.Query) {
+		pattern := "%" + likeEscaper.Replace(word) + "%"
+		query = query.Where(goqu.L(`collections.title LIKE ? ESCAPE '\'`, pattern))
+	}
+
+	return db.getPagedCollection(ctx, query, opts)
+}
+
+func (db DB) GetPagedDeletedCollection(ctx context.Context, opts FetchOptions) ([]Collection, types.Page, error) {
+	query := CollectionQuery().
+		Where(goqu.I("collections.deleted").IsNotNull()).
+		Order(goqu.I("collections.deleted").Desc())
+
+	return db.getPagedCollection(ctx, query, opts)
+}
 
+func (db DB) getPagedCollection(ctx context.Context, query *goqu.SelectDataset, opts FetchOptions) ([]Collection, types.Page, error) {
 	countQuery := query.
 		Select(goqu.COUNT("collections.id"))
 
@@ -78,22 +181,152 @@ func (db DB) GetPagedCollection(ctx context.Context, opts FetchOptions) ([]Colle
 }
 
 func (db DB) GetAllCollection(ctx context.Context) ([]Collection, error) {
-	query := CollectionQuery()
+	query := CollectionQuery().
+		Where(goqu.I("collections.deleted").IsNull())
+
+	return ember.Multiple[Collection](db.db, ctx, query)
+}
+
+// NOTE(patrik): The pattern is a LIKE pattern matched against the title
+// without the surrounding spaces, \ is used as the escape character
+func (db DB) GetCollectionsByTitleLike(ctx context.Context, pattern string) ([]Collection, error) {
+	query := CollectionQuery().
+		Where(
+			goqu.I("collections.deleted").IsNull(),
+			goqu.L(`TRIM(collections.title) LIKE ? ESCAPE '\'`, pattern),
+		)
+
+	return ember.Multiple[Collection](db.db, ctx, query)
+}
+
+func (db DB) GetRecentCollections(ctx context.Context, limit int) ([]Collection, error) {
+	query := CollectionQuery().
+		Where(goqu.I("collections.deleted").IsNull()).
+		Order(goqu.I("collections.created").Desc()).
+		Limit(uint(limit))
+
+	return ember.Multiple[Collection](db.db, ctx, query)
+}
+
+type Series struct {
+	Name    string `db:"name"`
+	Count   int    `db:"count"`
+	Updated int64  `db:"updated"`
+}
+
+func (db DB) GetAllSeries(ctx context.Context) ([]Series, error) {
+	query := dialect.From("collections").
+		Select(
+			goqu.I("collections.series").As("name"),
+			goqu.COUNT("collections.id").As("count"),
+			goqu.MAX("collections.updated").As("updated"),
+		).
+		Where(
+			goqu.I("collections.deleted").IsNull(),
+			goqu.I("collections.series").IsNotNull(),
+		).
+		GroupBy("collections.series").
+		Order(goqu.L("collections.series COLLATE NOCASE").Asc())
+
+	return ember.Multiple[Series](db.db, ctx, query)
+}
+
+// NOTE(patrik): Sorted by volume, collections without a volume are placed
+// last
+func (db DB) GetCollectionsBySeries(ctx context.Context, series string) ([]Collection, error) {
+	query := CollectionQuery().
+		Where(
+			goqu.I("collections.deleted").IsNull(),
+			goqu.I("collections.series").Eq(series),
+		).
+		Order(
+			goqu.I("collections.volume").Asc().NullsLast(),
+			goqu.I("collections.title").Asc(),
+		)
+
 	return ember.Multiple[Collection](db.db, ctx, query)
 }
 
+// NOTE(patrik): Includes the collections inside the trash
+func (db DB) GetCollectionsWithCustomCover(ctx context.Context) ([]Collection, error) {
+	query := CollectionQuery().
+		Where(goqu.I("collections.cover_hash").IsNotNull())
+
+	return ember.Multiple[Collection](db.db, ctx, query)
+}
+
+// NOTE(patrik): Includes the collections inside the trash
+func (db DB) GetAllCollectionIds(ctx context.Context) ([]string, error) {
+	query := dialect.From("collections").
+		Select("collections.id")
+
+	return ember.Multiple[string](db.db, ctx, query)
+}
+
+func (db DB) GetCollectionsWithRelease(ctx context.Context) ([]Collection, error) {
+	query := CollectionQuery().
+		Where(
+			goqu.I("collections.deleted").IsNull(),
+			goqu.I("collections.release_start").IsNotNull(),
+		)
+
+	return ember.Multiple[Collection](db.db, ctx, query)
+}
+
+// NOTE(patrik): Includes the collections inside the trash
+func (db DB) HasCollectionWithSourceHash(ctx context.Context, hash string) (bool, error) {
+	query := dialect.From("collections").
+		Select(goqu.COUNT("*")).
+		Where(goqu.I("collections.source_hash").Eq(hash))
+
+	count, err := ember.Single[int](db.db, ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (db DB) GetCollectionById(ctx context.Context, id string) (Collection, error) {
 	query := CollectionQuery().
-		Where(goqu.I("collections.id").Eq(id))
+		Where(
+			goqu.I("collections.id").Eq(id),
+			goqu.I("collections.deleted").IsNull(),
+		)
+
+	return ember.Single[Collection](db.db, ctx, query)
+}
+
+func (db DB) GetDeletedCollectionById(ctx context.Context, id string) (Collection, error) {
+	query := CollectionQuery().
+		Where(
+			goqu.I("collections.id").Eq(id),
+			goqu.I("collections.deleted").IsNotNull(),
+		)
 
 	return ember.Single[Collection](db.db, ctx, query)
 }
 
+// NOTE(patrik): Returns all the collections that was moved to the trash
+// before the given time (unix milliseconds)
+func (db DB) GetCollectionsDeletedBefore(ctx context.Context, t int64) ([]Collection, error) {
+	query := CollectionQuery().
+		Where(
+			goqu.I("collections.deleted").IsNotNull(),
+			goqu.I("collections.deleted").Lt(t),
+		)
+
+	return ember.Multiple[Collection](db.db, ctx, query)
+}
+
 type CreateCollectionParams struct {
 	Id   string
 
 	Title string
 
+	Series sql.NullString
+	Volume sql.NullInt64
+
 	Created int64
 	Updated int64
 }
@@ -118,6 +351,9 @@ func (db DB) CreateCollection(ctx context.Context, params CreateCollectionParams
 
 		"title":       params.Title,
 
+		"series": params.Series,
+		"volume": params.Volume,
+
 		"created": created,
 		"updated": updated,
 	}).
@@ -129,6 +365,28 @@ func (db DB) CreateCollection(ctx context.Context, params CreateCollectionParams
 type CollectionChanges struct {
 	Title       Change[string]
 
+	SourceHash Change[sql.NullString]
+
+	Deleted Change[sql.NullInt64]
+
+	Series Change[sql.NullString]
+	Volume Change[sql.NullInt64]
+
+	ReleaseStart        Change[sql.NullString]
+	ReleaseDelayDays    Change[int]
+	ReleaseIntervalDays Change[int]
+	ReleaseNumParts     Change[int]
+	ReleaseNotifiedPart Change[int]
+
+	ReaderDirection Change[sql.NullString]
+	ReaderLayout    Change[sql.NullString]
+	ReaderFit       Change[sql.NullString]
+
+	CoverImageId       Change[sql.NullString]
+	CoverHash          Change[sql.NullString]
+	CoverHashAlgorithm Change[sql.NullString]
+	CoverFilename      Change[sql.NullString]
+
 	Created Change[int64]
 }
 
@@ -137,6 +395,28 @@ func (db DB) UpdateCollection(ctx context.Context, id string, changes Collection
 
 	addToRecord(record, "title", changes.Title)
 
+	addToRecord(record, "source_hash", changes.SourceHash)
+
+	addToRecord(record, "deleted", changes.Deleted)
+
+	addToRecord(record, "series", changes.Series)
+	addToRecord(record, "volume", changes.Volume)
+
+	addToRecord(record, "release_start", changes.ReleaseStart)
+	addToRecord(record, "release_delay_days", changes.ReleaseDelayDays)
+	addToRecord(record, "release_interval_days", changes.ReleaseIntervalDays)
+	addToRecord(record, "release_num_parts", changes.ReleaseNumParts)
+	addToRecord(record, "release_notified_part", changes.ReleaseNotifiedPart)
+
+	addToRecord(record, "reader_direction", changes.ReaderDirection)
+	addToRecord(record, "reader_layout", changes.ReaderLayout)
+	addToRecord(record, "reader_fit", changes.ReaderFit)
+
+	addToRecord(record, "cover_image_id", changes.CoverImageId)
+	addToRecord(record, "cover_hash", changes.CoverHash)
+	addToRecord(record, "cover_hash_algorithm", changes.CoverHashAlgorithm)
+	addToRecord(record, "cover_filename", changes.CoverFilename)
+
 	addToRecord(record, "created", changes.Created)
 
 	if len(record) == 0 {
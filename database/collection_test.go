@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestGetSeries(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	create := func(id, title, series string, volume int64) {
+		t.Helper()
+
+		_, err := db.CreateCollection(ctx, CreateCollectionParams{
+			Id:     id,
+			Title:  title,
+			Series: sql.NullString{String: series, Valid: series != ""},
+			Volume: sql.NullInt64{Int64: volume, Valid: volume > 0},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	create("a3", "Series A Extra", "Series A", 0)
+	create("a2", "Series A 2", "Series A", 2)
+	create("a1", "Series A 1", "Series A", 1)
+	create("b1", "Series B 1", "series b", 1)
+	create("c", "Standalone", "", 0)
+
+	series, err := db.GetAllSeries(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(series) != 2 {
+		t.Fatalf("expected 2 series, got %+v", series)
+	}
+
+	if series[0].Name != "Series A" || series[0].Count != 3 || series[1].Name != "series b" || series[1].Count != 1 {
+		t.Fatalf("unexpected series %+v", series)
+	}
+
+	collections, err := db.GetCollectionsBySeries(ctx, "Series A")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, collection := range collections {
+		ids = append(ids, collection.Id)
+	}
+
+	if len(ids) != 3 || ids[0] != "a1" || ids[1] != "a2" || ids[2] != "a3" {
+		t.Fatalf("unexpected order %v", ids)
+	}
+}
@@ -45,10 +45,26 @@ func (db *Database) RunMigrateUp() error {
 	return migrations.RunMigrateUp(db.db.DB.DB)
 }
 
+func (db *Database) RunMigrateUpTo(version int64) error {
+	return migrations.RunMigrateUpTo(db.db.DB.DB, version)
+}
+
 func (db *Database) RunMigrateDown() error {
 	return migrations.RunMigrateDown(db.db.DB.DB)
 }
 
+func (db *Database) RunMigrateRedo() error {
+	return migrations.RunMigrateRedo(db.db.DB.DB)
+}
+
+func (db *Database) RunMigrateStatus() error {
+	return migrations.RunMigrateStatus(db.db.DB.DB)
+}
+
+func (db *Database) MigrationVersion() (int64, error) {
+	return migrations.GetVersion(db.db.DB.DB)
+}
+
 func (db *Database) Begin() (Tx, error) {
 	tx, err := db.db.Begin()
 	if err != nil {
@@ -81,6 +97,10 @@ func Open(dbFile string) (*Database, error) {
 	}, nil
 }
 
+func (db *Database) Close() error {
+	return db.db.DB.DB.Close()
+}
+
 func handleErr(err error) error {
 	if errors.Is(err, sql.ErrNoRows) {
 		return ErrItemNotFound
@@ -89,7 +109,7 @@ func handleErr(err error) error {
 	var e sqlite3.Error
 	if errors.As(err, &e) {
 		switch e.ExtendedCode {
-		case sqlite3.ErrConstraintPrimaryKey:
+		case sqlite3.ErrConstraintPrimaryKey, sqlite3.ErrConstraintUnique:
 			return ErrItemAlreadyExists
 		}
 	}
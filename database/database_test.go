@@ -0,0 +1,26 @@
+package database
+
+import (
+	"path"
+	"testing"
+)
+
+func openTestDB(t *testing.T) *Database {
+	t.Helper()
+
+	db, err := Open(path.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	err = db.RunMigrateUp()
+	if err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	return db
+}
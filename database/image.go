@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/doug-martin/goqu/v9"
@@ -13,10 +14,35 @@ import (
 type Image struct {
 	RowId int `db:"rowid"`
 
+	Id           string `db:"id"`
 	CollectionId string `db:"collection_id"`
 	Hash         string `db:"hash"`
 
+	HashAlgorithm types.HashAlgorithm `db:"hash_algorithm"`
+
+	// NOTE(patrik): Perceptual hash (dHash) stored as hex, null when the
+	// image hasn't been analyzed
+	PHash sql.NullString `db:"phash"`
+
+	// NOTE(patrik): Metadata extracted from the content, null when the
+	// image hasn't been analyzed or couldn't be decoded
+	Width     sql.NullInt64  `db:"width"`
+	Height    sql.NullInt64  `db:"height"`
+	Size      sql.NullInt64  `db:"size"`
+	MimeType  sql.NullString `db:"mime_type"`
+	ColorMode sql.NullString `db:"color_mode"`
+	Animated  sql.NullBool   `db:"animated"`
+
+	// NOTE(patrik): Split is set on a spread that has been split into two
+	// pages, the pages point back to the spread with SourceId
+	Split      sql.NullInt64  `db:"split"`
+	SourceId   sql.NullString `db:"source_id"`
+	SpreadSide sql.NullString `db:"spread_side"`
+
 	Filename string `db:"filename"`
+	Position int    `db:"position"`
+
+	Verified sql.NullInt64 `db:"verified"`
 
 	Created int64 `db:"created"`
 	Updated int64 `db:"updated"`
@@ -28,10 +54,29 @@ func ImageQuery() *goqu.SelectDataset {
 		Select(
 			"images.rowid",
 
+			"images.id",
 			"images.collection_id",
 			"images.hash",
 
+			"images.hash_algorithm",
+
+			"images.phash",
+
+			"images.width",
+			"images.height",
+			"images.size",
+			"images.mime_type",
+			"images.color_mode",
+			"images.animated",
+
+			"images.split",
+			"images.source_id",
+			"images.spread_side",
+
 			"images.filename",
+			"images.position",
+
+			"images.verified",
 
 			"images.created",
 			"images.updated",
@@ -77,6 +122,10 @@ func (db DB) GetPagedImagesByCollectionId(ctx context.Context, collectionId stri
 	query := ImageQuery().
 		Where(
 			goqu.I("images.collection_id").Eq(collectionId),
+		).
+		Order(
+			goqu.I("images.position").Asc(),
+			goqu.I("images.created").Asc(),
 		)
 
 	countQuery := query.
@@ -114,18 +163,109 @@ func (db DB) GetAllImages(ctx context.Context) ([]Image, error) {
 	return ember.Multiple[Image](db.db, ctx, query)
 }
 
+// NOTE(patrik): Spreads that has been split are not counted, the pages
+// created from them are counted instead
+func (db DB) CountCollectionPages(ctx context.Context, collectionId string) (int, error) {
+	query := dialect.From("images").
+		Select(goqu.COUNT("images.id")).
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.split").IsNull(),
+		)
+
+	return ember.Single[int](db.db, ctx, query)
+}
+
 func (db DB) GetAllImagesByCollectionId(ctx context.Context, collectionId string) ([]Image, error) {
 	query := ImageQuery().
 		Where(
 			goqu.I("images.collection_id").Eq(collectionId),
 		).
 		Order(
+			goqu.I("images.position").Asc(),
 			goqu.I("images.created").Asc(),
 		)
 
 	return ember.Multiple[Image](db.db, ctx, query)
 }
 
+// NOTE(patrik): Returns the images that have never been verified or have
+// been changed since they were last verified, all is used to get every
+// image
+func (db DB) GetImagesToVerify(ctx context.Context, all bool) ([]Image, error) {
+	query := ImageQuery().
+		Order(goqu.I("images.collection_id").Asc(), goqu.I("images.position").Asc())
+
+	if !all {
+		query = query.Where(
+			goqu.Or(
+				goqu.I("images.verified").IsNull(),
+				goqu.I("images.verified").Lt(goqu.I("images.updated")),
+			),
+		)
+	}
+
+	return ember.Multiple[Image](db.db, ctx, query)
+}
+
+// NOTE(patrik): Doesn't touch the updated column because the image itself
+// hasn't changed
+func (db DB) MarkImageVerified(ctx context.Context, collectionId, id string, t int64) error {
+	query := dialect.Update("images").
+		Set(goqu.Record{
+			"verified": t,
+		}).
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.id").Eq(id),
+		)
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NOTE(patrik): Returns the images that has a perceptual hash, images
+// inside the trash are skipped
+func (db DB) GetImagesWithPHash(ctx context.Context) ([]Image, error) {
+	query := ImageQuery().
+		Join(
+			goqu.I("collections"),
+			goqu.On(goqu.I("collections.id").Eq(goqu.I("images.collection_id"))),
+		).
+		Where(
+			goqu.I("collections.deleted").IsNull(),
+			goqu.I("images.phash").IsNotNull(),
+		).
+		Order(goqu.I("images.collection_id").Asc(), goqu.I("images.position").Asc())
+
+	return ember.Multiple[Image](db.db, ctx, query)
+}
+
+// NOTE(patrik): Returns the images that are missing the data extracted by
+// the analyze step
+func (db DB) GetImagesToAnalyze(ctx context.Context) ([]Image, error) {
+	query := ImageQuery().
+		Where(
+			goqu.Or(
+				goqu.I("images.phash").IsNull(),
+				goqu.I("images.size").IsNull(),
+			),
+		)
+
+	return ember.Multiple[Image](db.db, ctx, query)
+}
+
+func (db DB) GetImagesNotUsingHashAlgorithm(ctx context.Context, alg types.HashAlgorithm) ([]Image, error) {
+	query := ImageQuery().
+		Where(goqu.I("images.hash_algorithm").Neq(alg))
+
+	return ember.Multiple[Image](db.db, ctx, query)
+}
+
 func (db DB) GetImageById(ctx context.Context, collectionId, id string) (Image, error) {
 	query := ImageQuery().
 		Where(
@@ -136,17 +276,79 @@ func (db DB) GetImageById(ctx context.Context, collectionId, id string) (Image,
 	return ember.Single[Image](db.db, ctx, query)
 }
 
+func (db DB) GetImageByFilename(ctx context.Context, collectionId, filename string) (Image, error) {
+	query := ImageQuery().
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.filename").Eq(filename),
+		).
+		Limit(1)
+
+	return ember.Single[Image](db.db, ctx, query)
+}
+
+func (db DB) GetImageByHash(ctx context.Context, collectionId, hash string) (Image, error) {
+	query := ImageQuery().
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.hash").Eq(hash),
+		)
+
+	return ember.Single[Image](db.db, ctx, query)
+}
+
+// NOTE(patrik): Returns the pages created from a spread
+func (db DB) GetImagesBySourceId(ctx context.Context, collectionId, sourceId string) ([]Image, error) {
+	query := ImageQuery().
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.source_id").Eq(sourceId),
+		).
+		Order(goqu.I("images.position").Desc())
+
+	return ember.Multiple[Image](db.db, ctx, query)
+}
+
+func (db DB) GetNextImagePosition(ctx context.Context, collectionId string) (int, error) {
+	query := dialect.From("images").
+		Select(goqu.L("COALESCE(MAX(?) + 1, 0)", goqu.I("images.position"))).
+		Where(goqu.I("images.collection_id").Eq(collectionId))
+
+	return ember.Single[int](db.db, ctx, query)
+}
+
 type CreateImageParams struct {
+	Id           string
 	CollectionId string
 	Hash         string
 
+	HashAlgorithm types.HashAlgorithm
+	PHash         sql.NullString
+
+	Width     sql.NullInt64
+	Height    sql.NullInt64
+	Size      sql.NullInt64
+	MimeType  sql.NullString
+	ColorMode sql.NullString
+	Animated  sql.NullBool
+
+	SourceId   sql.NullString
+	SpreadSide sql.NullString
+
 	Filename string
+	Position int
+
+	// NOTE(patrik): Places the image after the last image in the
+	// collection instead of at Position, the position is picked by the
+	// insert so images appended at the same time can't get the same
+	// position
+	Append bool
 
 	Created int64
 	Updated int64
 }
 
-func (db DB) CreateImage(ctx context.Context, params CreateImageParams) error {
+func (db DB) CreateImage(ctx context.Context, params CreateImageParams) (string, error) {
 	t := time.Now().UnixMilli()
 	created := params.Created
 	updated := params.Updated
@@ -156,15 +358,105 @@ func (db DB) CreateImage(ctx context.Context, params CreateImageParams) error {
 		updated = t
 	}
 
+	id := params.Id
+	if id == "" {
+		id = utils.CreateImageId()
+	}
+
+	var position any = params.Position
+	if params.Append {
+		position = goqu.L(
+			"(SELECT COALESCE(MAX(position) + 1, 0) FROM images WHERE collection_id = ?)",
+			params.CollectionId,
+		)
+	}
+
 	query := dialect.Insert("images").Rows(goqu.Record{
+		"id":            id,
 		"collection_id": params.CollectionId,
 		"hash":          params.Hash,
 
+		"hash_algorithm": params.HashAlgorithm,
+		"phash":          params.PHash,
+
+		"width":      params.Width,
+		"height":     params.Height,
+		"size":       params.Size,
+		"mime_type":  params.MimeType,
+		"color_mode": params.ColorMode,
+		"animated":   params.Animated,
+
+		"source_id":   params.SourceId,
+		"spread_side": params.SpreadSide,
+
 		"filename": params.Filename,
+		"position": position,
 
 		"created": created,
 		"updated": updated,
-	})
+	}).
+		Returning("id")
+
+	return ember.Single[string](db.db, ctx, query)
+}
+
+type ImageChanges struct {
+	Hash          Change[string]
+	HashAlgorithm Change[types.HashAlgorithm]
+	PHash         Change[sql.NullString]
+
+	Width     Change[sql.NullInt64]
+	Height    Change[sql.NullInt64]
+	Size      Change[sql.NullInt64]
+	MimeType  Change[sql.NullString]
+	ColorMode Change[sql.NullString]
+	Animated  Change[sql.NullBool]
+
+	Split Change[sql.NullInt64]
+
+	Filename Change[string]
+	Position Change[int]
+
+	Verified Change[sql.NullInt64]
+
+	Created Change[int64]
+}
+
+func (db DB) UpdateImage(ctx context.Context, collectionId, id string, changes ImageChanges) error {
+	record := goqu.Record{}
+
+	addToRecord(record, "hash", changes.Hash)
+	addToRecord(record, "hash_algorithm", changes.HashAlgorithm)
+	addToRecord(record, "phash", changes.PHash)
+
+	addToRecord(record, "width", changes.Width)
+	addToRecord(record, "height", changes.Height)
+	addToRecord(record, "size", changes.Size)
+	addToRecord(record, "mime_type", changes.MimeType)
+	addToRecord(record, "color_mode", changes.ColorMode)
+	addToRecord(record, "animated", changes.Animated)
+
+	addToRecord(record, "split", changes.Split)
+
+	addToRecord(record, "filename", changes.Filename)
+	addToRecord(record, "position", changes.Position)
+
+	addToRecord(record, "verified", changes.Verified)
+
+	addToRecord(record, "created", changes.Created)
+
+	if len(record) == 0 {
+		return nil
+	}
+
+	record["updated"] = time.Now().UnixMilli()
+
+	query := dialect.Update("images").
+		Set(record).
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.id").Eq(id),
+		)
 
 	_, err := db.db.Exec(ctx, query)
 	if err != nil {
@@ -174,36 +466,42 @@ func (db DB) CreateImage(ctx context.Context, params CreateImageParams) error {
 	return nil
 }
 
-// type ImageChanges struct {
-// 	Title Change[string]
-//
-// 	Created Change[int64]
-// }
-//
-// func (db DB) UpdateImage(ctx context.Context, id string, changes ImageChanges) error {
-// 	record := goqu.Record{}
-//
-// 	addToRecord(record, "title", changes.Title)
-//
-// 	addToRecord(record, "created", changes.Created)
-//
-// 	if len(record) == 0 {
-// 		return nil
-// 	}
-//
-// 	record["updated"] = time.Now().UnixMilli()
-//
-// 	query := dialect.Update("images").
-// 		Set(record).
-// 		Where(goqu.I("images.id").Eq(id))
-//
-// 	_, err := db.db.Exec(ctx, query)
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	return nil
-// }
+// NOTE(patrik): Adds delta to the position of every image in the
+// collection with a position inside the range [from, to]. The positions
+// are unique and the constraint is checked for each row, so the images
+// are first moved to negative positions and then flipped back
+func (db DB) ShiftImagePositions(ctx context.Context, collectionId string, from, to, delta int) error {
+	query := dialect.Update("images").
+		Set(goqu.Record{
+			"position": goqu.L("-(? + ?) - 1", goqu.I("position"), delta),
+		}).
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.position").Gte(from),
+			goqu.I("images.position").Lte(to),
+		)
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	query = dialect.Update("images").
+		Set(goqu.Record{
+			"position": goqu.L("-? - 1", goqu.I("position")),
+		}).
+		Where(
+			goqu.I("images.collection_id").Eq(collectionId),
+			goqu.I("images.position").Lt(0),
+		)
+
+	_, err = db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
 
 func (db DB) RemoveImage(ctx context.Context, collectionId, id string) error {
 	query := dialect.Delete("images").
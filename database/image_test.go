@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nanoteck137/storebook/types"
+)
+
+func TestImagePositions(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.CreateCollection(ctx, CreateCollectionParams{Id: "c1", Title: "Test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(hash string, position int, appendImage bool) (string, error) {
+		return db.CreateImage(ctx, CreateImageParams{
+			CollectionId:  "c1",
+			Hash:          hash,
+			HashAlgorithm: types.HashAlgorithmSHA256,
+			Filename:      hash + ".png",
+			Position:      position,
+			Append:        appendImage,
+		})
+	}
+
+	for i, hash := range []string{"aaaa", "bbbb", "cccc"} {
+		_, err := create(hash, 0, true)
+		if err != nil {
+			t.Fatalf("failed to append image %d: %v", i, err)
+		}
+	}
+
+	_, err = create("dddd", 1, false)
+	if !errors.Is(err, ErrItemAlreadyExists) {
+		t.Fatalf("expected a duplicated position to fail, got %v", err)
+	}
+
+	// NOTE(patrik): Make room at the start, every row in the range is moved
+	// onto a position that is taken before the update
+	err = db.ShiftImagePositions(ctx, "c1", 0, 2, 1)
+	if err != nil {
+		t.Fatalf("failed to shift positions: %v", err)
+	}
+
+	_, err = create("dddd", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images, err := db.GetAllImagesByCollectionId(ctx, "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"dddd", "aaaa", "bbbb", "cccc"}
+	for i, img := range images {
+		if img.Hash != expected[i] || img.Position != i {
+			t.Fatalf("image %d is %s at %d, expected %s at %d", i, img.Hash, img.Position, expected[i], i)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/nanoteck137/pyrin/ember"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+type Job struct {
+	RowId int `db:"rowid"`
+
+	Id string `db:"id"`
+
+	Type   string          `db:"type"`
+	Status types.JobStatus `db:"status"`
+
+	Payload string         `db:"payload"`
+	Result  sql.NullString `db:"result"`
+	Error   sql.NullString `db:"error"`
+
+	ProgressCurrent int `db:"progress_current"`
+	ProgressTotal   int `db:"progress_total"`
+
+	Attempts int `db:"attempts"`
+
+	Started  sql.NullInt64 `db:"started"`
+	Finished sql.NullInt64 `db:"finished"`
+
+	Created int64 `db:"created"`
+	Updated int64 `db:"updated"`
+}
+
+type JobLog struct {
+	JobId string `db:"job_id"`
+
+	Message string `db:"message"`
+
+	Created int64 `db:"created"`
+}
+
+// TODO(patrik): Use goqu.T more
+func JobQuery() *goqu.SelectDataset {
+	query := dialect.From("jobs").
+		Select(
+			"jobs.rowid",
+
+			"jobs.id",
+
+			"jobs.type",
+			"jobs.status",
+
+			"jobs.payload",
+			"jobs.result",
+			"jobs.error",
+
+			"jobs.progress_current",
+			"jobs.progress_total",
+
+			"jobs.attempts",
+
+			"jobs.started",
+			"jobs.finished",
+
+			"jobs.created",
+			"jobs.updated",
+		)
+
+	return query
+}
+
+type JobFilter struct {
+	Status types.JobStatus
+	Type   string
+}
+
+func (db DB) GetPagedJobs(ctx context.Context, filter JobFilter, opts FetchOptions) ([]Job, types.Page, error) {
+	query := JobQuery().
+		Order(goqu.I("jobs.created").Desc())
+
+	if filter.Status != "" {
+		query = query.Where(goqu.I("jobs.status").Eq(filter.Status))
+	}
+
+	if filter.Type != "" {
+		query = query.Where(goqu.I("jobs.type").Eq(filter.Type))
+	}
+
+	countQuery := query.
+		Select(goqu.COUNT("jobs.id"))
+
+	if opts.PerPage > 0 {
+		query = query.
+			Limit(uint(opts.PerPage)).
+			Offset(uint(opts.Page * opts.PerPage))
+	}
+
+	totalItems, err := ember.Single[int](db.db, ctx, countQuery)
+	if err != nil {
+		return nil, types.Page{}, err
+	}
+
+	totalPages := utils.TotalPages(opts.PerPage, totalItems)
+	page := types.Page{
+		Page:       opts.Page,
+		PerPage:    opts.PerPage,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}
+
+	items, err := ember.Multiple[Job](db.db, ctx, query)
+	if err != nil {
+		return nil, types.Page{}, err
+	}
+
+	return items, page, nil
+}
+
+func (db DB) GetJobById(ctx context.Context, id string) (Job, error) {
+	query := JobQuery().
+		Where(goqu.I("jobs.id").Eq(id))
+
+	return ember.Single[Job](db.db, ctx, query)
+}
+
+// NOTE(patrik): Marks the oldest queued job as running and returns it,
+// returns ErrItemNotFound when the queue is empty
+func (db DB) ClaimNextJob(ctx context.Context) (Job, error) {
+	t := time.Now().UnixMilli()
+
+	next := dialect.From("jobs").
+		Select("jobs.id").
+		Where(goqu.I("jobs.status").Eq(types.JobStatusQueued)).
+		Order(goqu.I("jobs.created").Asc()).
+		Limit(1)
+
+	query := dialect.Update("jobs").
+		Set(goqu.Record{
+			"status":   types.JobStatusRunning,
+			"attempts": goqu.L("? + 1", goqu.I("attempts")),
+			"started":  t,
+			"updated":  t,
+		}).
+		Where(goqu.I("jobs.id").Eq(next)).
+		Returning("id")
+
+	id, err := ember.Single[string](db.db, ctx, query)
+	if err != nil {
+		return Job{}, err
+	}
+
+	return db.GetJobById(ctx, id)
+}
+
+// NOTE(patrik): Only cancels the job if it's still queued so a job
+// claimed by a worker at the same time isn't marked as cancelled while
+// it's running, returns ErrItemNotFound when the job wasn't queued
+func (db DB) CancelQueuedJob(ctx context.Context, id string) error {
+	t := time.Now().UnixMilli()
+
+	query := dialect.Update("jobs").
+		Set(goqu.Record{
+			"status":   types.JobStatusCancelled,
+			"finished": t,
+			"updated":  t,
+		}).
+		Where(
+			goqu.I("jobs.id").Eq(id),
+			goqu.I("jobs.status").Eq(types.JobStatusQueued),
+		).
+		Returning("id")
+
+	_, err := ember.Single[string](db.db, ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NOTE(patrik): Puts jobs that was running when the server stopped back
+// into the queue
+func (db DB) RequeueRunningJobs(ctx context.Context) error {
+	query := dialect.Update("jobs").
+		Set(goqu.Record{
+			"status":  types.JobStatusQueued,
+			"updated": time.Now().UnixMilli(),
+		}).
+		Where(goqu.I("jobs.status").Eq(types.JobStatusRunning))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type CreateJobParams struct {
+	Id string
+
+	Type   string
+	Status types.JobStatus
+
+	Payload string
+
+	Created int64
+	Updated int64
+}
+
+func (db DB) CreateJob(ctx context.Context, params CreateJobParams) (string, error) {
+	t := time.Now().UnixMilli()
+	created := params.Created
+	updated := params.Updated
+
+	if created == 0 && updated == 0 {
+		created = t
+		updated = t
+	}
+
+	id := params.Id
+	if id == "" {
+		id = utils.CreateJobId()
+	}
+
+	status := params.Status
+	if status == "" {
+		status = types.JobStatusQueued
+	}
+
+	payload := params.Payload
+	if payload == "" {
+		payload = "{}"
+	}
+
+	query := dialect.Insert("jobs").Rows(goqu.Record{
+		"id": id,
+
+		"type":   params.Type,
+		"status": status,
+
+		"payload": payload,
+
+		"created": created,
+		"updated": updated,
+	}).
+		Returning("id")
+
+	return ember.Single[string](db.db, ctx, query)
+}
+
+type JobChanges struct {
+	Status Change[types.JobStatus]
+
+	Result Change[sql.NullString]
+	Error  Change[sql.NullString]
+
+	ProgressCurrent Change[int]
+	ProgressTotal   Change[int]
+
+	Started  Change[sql.NullInt64]
+	Finished Change[sql.NullInt64]
+}
+
+func (db DB) UpdateJob(ctx context.Context, id string, changes JobChanges) error {
+	record := goqu.Record{}
+
+	addToRecord(record, "status", changes.Status)
+
+	addToRecord(record, "result", changes.Result)
+	addToRecord(record, "error", changes.Error)
+
+	addToRecord(record, "progress_current", changes.ProgressCurrent)
+	addToRecord(record, "progress_total", changes.ProgressTotal)
+
+	addToRecord(record, "started", changes.Started)
+	addToRecord(record, "finished", changes.Finished)
+
+	if len(record) == 0 {
+		return nil
+	}
+
+	record["updated"] = time.Now().UnixMilli()
+
+	query := dialect.Update("jobs").
+		Set(record).
+		Where(goqu.I("jobs.id").Eq(id))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db DB) RemoveJob(ctx context.Context, id string) error {
+	query := dialect.Delete("jobs").
+		Where(goqu.I("jobs.id").Eq(id))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db DB) GetJobLogs(ctx context.Context, jobId string) ([]JobLog, error) {
+	query := dialect.From("job_logs").
+		Select(
+			"job_logs.job_id",
+
+			"job_logs.message",
+
+			"job_logs.created",
+		).
+		Where(goqu.I("job_logs.job_id").Eq(jobId)).
+		Order(goqu.I("job_logs.rowid").Asc())
+
+	return ember.Multiple[JobLog](db.db, ctx, query)
+}
+
+func (db DB) CreateJobLog(ctx context.Context, jobId, message string) error {
+	query := dialect.Insert("job_logs").Rows(goqu.Record{
+		"job_id": jobId,
+
+		"message": message,
+
+		"created": time.Now().UnixMilli(),
+	})
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db DB) RemoveJobLogs(ctx context.Context, jobId string) error {
+	query := dialect.Delete("job_logs").
+		Where(goqu.I("job_logs.job_id").Eq(jobId))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE images_new (
+    id TEXT NOT NULL CHECK(id<>''),
+    collection_id TEXT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    hash TEXT NOT NULL CHECK(hash<>''),
+
+	filename TEXT NOT NULL CHECK(filename<>''),
+	position INTEGER NOT NULL,
+
+    created INTEGER NOT NULL,
+    updated INTEGER NOT NULL,
+
+    PRIMARY KEY(collection_id, id),
+    UNIQUE(collection_id, hash)
+);
+
+INSERT INTO images_new (id, collection_id, hash, filename, position, created, updated)
+SELECT
+    lower(hex(randomblob(4))),
+    collection_id,
+    hash,
+    filename,
+    ROW_NUMBER() OVER (PARTITION BY collection_id ORDER BY created, rowid) - 1,
+    created,
+    updated
+FROM images;
+
+DROP TABLE images;
+ALTER TABLE images_new RENAME TO images;
+
+-- +goose Down
+CREATE TABLE images_old (
+    collection_id TEXT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    hash TEXT NOT NULL CHECK(hash<>''),
+
+	filename TEXT NOT NULL CHECK(filename<>''),
+
+    created INTEGER NOT NULL,
+    updated INTEGER NOT NULL,
+
+    PRIMARY KEY(collection_id, hash)
+);
+
+INSERT INTO images_old (collection_id, hash, filename, created, updated)
+SELECT collection_id, hash, filename, created, updated FROM images;
+
+DROP TABLE images;
+ALTER TABLE images_old RENAME TO images;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN deleted INTEGER;
+
+-- +goose Down
+ALTER TABLE collections DROP COLUMN deleted;
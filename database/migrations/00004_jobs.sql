@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE jobs (
+    id TEXT PRIMARY KEY,
+
+	type TEXT NOT NULL CHECK(type<>''),
+	status TEXT NOT NULL CHECK(status<>''),
+
+	payload TEXT NOT NULL,
+	result TEXT,
+	error TEXT,
+
+	progress_current INTEGER NOT NULL DEFAULT 0,
+	progress_total INTEGER NOT NULL DEFAULT 0,
+
+	attempts INTEGER NOT NULL DEFAULT 0,
+
+	started INTEGER,
+	finished INTEGER,
+
+    created INTEGER NOT NULL,
+    updated INTEGER NOT NULL
+);
+
+CREATE INDEX jobs_status_idx ON jobs(status, created);
+
+CREATE TABLE job_logs (
+    job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+
+	message TEXT NOT NULL,
+
+    created INTEGER NOT NULL
+);
+
+CREATE INDEX job_logs_job_id_idx ON job_logs(job_id);
+
+-- +goose Down
+DROP TABLE job_logs;
+DROP TABLE jobs;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE notifications (
+    id TEXT PRIMARY KEY,
+
+	type TEXT NOT NULL CHECK(type<>''),
+
+	title TEXT NOT NULL CHECK(title<>''),
+	message TEXT NOT NULL,
+
+	collection_id TEXT REFERENCES collections(id) ON DELETE CASCADE,
+	job_id TEXT REFERENCES jobs(id) ON DELETE SET NULL,
+
+	read INTEGER,
+
+    created INTEGER NOT NULL,
+    updated INTEGER NOT NULL
+);
+
+CREATE INDEX notifications_read_idx ON notifications(read, created);
+
+-- +goose Down
+DROP TABLE notifications;
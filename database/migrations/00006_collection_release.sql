@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN release_start TEXT;
+ALTER TABLE collections ADD COLUMN release_delay_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE collections ADD COLUMN release_interval_days INTEGER NOT NULL DEFAULT 7;
+ALTER TABLE collections ADD COLUMN release_num_parts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE collections ADD COLUMN release_notified_part INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE collections DROP COLUMN release_notified_part;
+ALTER TABLE collections DROP COLUMN release_num_parts;
+ALTER TABLE collections DROP COLUMN release_interval_days;
+ALTER TABLE collections DROP COLUMN release_delay_days;
+ALTER TABLE collections DROP COLUMN release_start;
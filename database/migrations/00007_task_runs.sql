@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE task_runs (
+    id TEXT PRIMARY KEY,
+
+	task TEXT NOT NULL CHECK(task<>''),
+	status TEXT NOT NULL CHECK(status<>''),
+	error TEXT,
+
+	started INTEGER NOT NULL,
+	finished INTEGER,
+
+    created INTEGER NOT NULL,
+    updated INTEGER NOT NULL
+);
+
+CREATE INDEX task_runs_task_idx ON task_runs(task, started);
+
+-- +goose Down
+DROP TABLE task_runs;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN verified INTEGER;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN verified;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN hash_algorithm TEXT NOT NULL DEFAULT 'md5';
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN hash_algorithm;
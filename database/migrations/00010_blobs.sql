@@ -0,0 +1,55 @@
+-- +goose Up
+CREATE TABLE blobs (
+    hash TEXT NOT NULL CHECK(hash<>''),
+    hash_algorithm TEXT NOT NULL,
+
+    size INTEGER NOT NULL DEFAULT 0,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+
+    created INTEGER NOT NULL,
+    updated INTEGER NOT NULL,
+
+    PRIMARY KEY(hash_algorithm, hash)
+);
+
+-- NOTE(patrik): The size gets filled in when the files are moved into the
+-- blob store on startup
+INSERT INTO blobs (hash, hash_algorithm, size, ref_count, created, updated)
+SELECT hash, hash_algorithm, 0, COUNT(*), MIN(created), MAX(updated)
+FROM images
+GROUP BY hash_algorithm, hash;
+
+-- +goose StatementBegin
+CREATE TRIGGER images_blob_insert AFTER INSERT ON images
+BEGIN
+    UPDATE blobs SET ref_count = ref_count + 1
+    WHERE hash_algorithm = NEW.hash_algorithm AND hash = NEW.hash;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER images_blob_delete AFTER DELETE ON images
+BEGIN
+    UPDATE blobs SET ref_count = ref_count - 1
+    WHERE hash_algorithm = OLD.hash_algorithm AND hash = OLD.hash;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER images_blob_update AFTER UPDATE OF hash, hash_algorithm ON images
+WHEN OLD.hash <> NEW.hash OR OLD.hash_algorithm <> NEW.hash_algorithm
+BEGIN
+    UPDATE blobs SET ref_count = ref_count - 1
+    WHERE hash_algorithm = OLD.hash_algorithm AND hash = OLD.hash;
+
+    UPDATE blobs SET ref_count = ref_count + 1
+    WHERE hash_algorithm = NEW.hash_algorithm AND hash = NEW.hash;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER images_blob_update;
+DROP TRIGGER images_blob_delete;
+DROP TRIGGER images_blob_insert;
+
+DROP TABLE blobs;
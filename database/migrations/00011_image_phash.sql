@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN phash TEXT;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN phash;
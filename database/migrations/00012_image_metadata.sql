@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE images ADD COLUMN width INTEGER;
+ALTER TABLE images ADD COLUMN height INTEGER;
+ALTER TABLE images ADD COLUMN size INTEGER;
+ALTER TABLE images ADD COLUMN mime_type TEXT;
+ALTER TABLE images ADD COLUMN color_mode TEXT;
+ALTER TABLE images ADD COLUMN animated INTEGER;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN animated;
+ALTER TABLE images DROP COLUMN color_mode;
+ALTER TABLE images DROP COLUMN mime_type;
+ALTER TABLE images DROP COLUMN size;
+ALTER TABLE images DROP COLUMN height;
+ALTER TABLE images DROP COLUMN width;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN reading_direction TEXT NOT NULL DEFAULT 'ltr';
+
+ALTER TABLE images ADD COLUMN split INTEGER;
+ALTER TABLE images ADD COLUMN source_id TEXT;
+ALTER TABLE images ADD COLUMN spread_side TEXT;
+
+-- +goose Down
+ALTER TABLE images DROP COLUMN spread_side;
+ALTER TABLE images DROP COLUMN source_id;
+ALTER TABLE images DROP COLUMN split;
+
+ALTER TABLE collections DROP COLUMN reading_direction;
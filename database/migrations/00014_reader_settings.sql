@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE settings (
+    key TEXT PRIMARY KEY CHECK(key<>''),
+    value TEXT NOT NULL,
+    updated INTEGER NOT NULL
+);
+
+-- NOTE(patrik): The reader settings on collections are overrides, null
+-- means that the default from the settings table is used
+ALTER TABLE collections ADD COLUMN reader_direction TEXT;
+ALTER TABLE collections ADD COLUMN reader_layout TEXT;
+ALTER TABLE collections ADD COLUMN reader_fit TEXT;
+
+UPDATE collections SET reader_direction = reading_direction WHERE reading_direction <> 'ltr';
+
+ALTER TABLE collections DROP COLUMN reading_direction;
+
+-- +goose Down
+ALTER TABLE collections ADD COLUMN reading_direction TEXT NOT NULL DEFAULT 'ltr';
+
+UPDATE collections SET reading_direction = 'rtl' WHERE reader_direction = 'rtl';
+
+ALTER TABLE collections DROP COLUMN reader_fit;
+ALTER TABLE collections DROP COLUMN reader_layout;
+ALTER TABLE collections DROP COLUMN reader_direction;
+
+DROP TABLE settings;
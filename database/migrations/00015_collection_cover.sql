@@ -0,0 +1,49 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN cover_image_id TEXT;
+
+ALTER TABLE collections ADD COLUMN cover_hash TEXT;
+ALTER TABLE collections ADD COLUMN cover_hash_algorithm TEXT;
+ALTER TABLE collections ADD COLUMN cover_filename TEXT;
+
+-- NOTE(patrik): Custom covers are stored inside the blob store so they
+-- need to be counted as references as well
+-- +goose StatementBegin
+CREATE TRIGGER collections_cover_blob_insert AFTER INSERT ON collections
+WHEN NEW.cover_hash IS NOT NULL
+BEGIN
+    UPDATE blobs SET ref_count = ref_count + 1
+    WHERE hash_algorithm = NEW.cover_hash_algorithm AND hash = NEW.cover_hash;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER collections_cover_blob_delete AFTER DELETE ON collections
+WHEN OLD.cover_hash IS NOT NULL
+BEGIN
+    UPDATE blobs SET ref_count = ref_count - 1
+    WHERE hash_algorithm = OLD.cover_hash_algorithm AND hash = OLD.cover_hash;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER collections_cover_blob_update AFTER UPDATE OF cover_hash, cover_hash_algorithm ON collections
+WHEN OLD.cover_hash IS NOT NEW.cover_hash OR OLD.cover_hash_algorithm IS NOT NEW.cover_hash_algorithm
+BEGIN
+    UPDATE blobs SET ref_count = ref_count - 1
+    WHERE hash_algorithm = OLD.cover_hash_algorithm AND hash = OLD.cover_hash;
+
+    UPDATE blobs SET ref_count = ref_count + 1
+    WHERE hash_algorithm = NEW.cover_hash_algorithm AND hash = NEW.cover_hash;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER collections_cover_blob_update;
+DROP TRIGGER collections_cover_blob_delete;
+DROP TRIGGER collections_cover_blob_insert;
+
+ALTER TABLE collections DROP COLUMN cover_filename;
+ALTER TABLE collections DROP COLUMN cover_hash_algorithm;
+ALTER TABLE collections DROP COLUMN cover_hash;
+
+ALTER TABLE collections DROP COLUMN cover_image_id;
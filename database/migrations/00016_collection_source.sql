@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN series TEXT;
+ALTER TABLE collections ADD COLUMN volume INTEGER;
+
+-- NOTE(patrik): Hash of the archive or the image folder the collection was
+-- imported from, used by the bulk import to skip what is already imported
+ALTER TABLE collections ADD COLUMN source_hash TEXT;
+
+CREATE INDEX collections_source_hash_idx ON collections(source_hash);
+
+-- +goose Down
+DROP INDEX collections_source_hash_idx;
+
+ALTER TABLE collections DROP COLUMN source_hash;
+
+ALTER TABLE collections DROP COLUMN volume;
+ALTER TABLE collections DROP COLUMN series;
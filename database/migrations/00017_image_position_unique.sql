@@ -0,0 +1,23 @@
+-- +goose Up
+-- NOTE(patrik): Concurrent imports could give images the same position,
+-- renumber the images in their current order before adding the constraint
+CREATE TEMP TABLE image_positions AS
+SELECT
+    rowid AS image_rowid,
+    ROW_NUMBER() OVER (
+        PARTITION BY collection_id
+        ORDER BY position, created, rowid
+    ) - 1 AS position
+FROM images;
+
+UPDATE images SET position = (
+    SELECT image_positions.position FROM image_positions
+    WHERE image_positions.image_rowid = images.rowid
+);
+
+DROP TABLE image_positions;
+
+CREATE UNIQUE INDEX images_collection_position_idx ON images(collection_id, position);
+
+-- +goose Down
+DROP INDEX images_collection_position_idx;
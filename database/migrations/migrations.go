@@ -20,6 +20,22 @@ func RunMigrateUp(conn *sql.DB) error {
 	return goose.Up(conn, ".")
 }
 
+func RunMigrateUpTo(conn *sql.DB, version int64) error {
+	return goose.UpTo(conn, ".", version)
+}
+
 func RunMigrateDown(conn *sql.DB) error {
 	return goose.Down(conn, ".")
 }
+
+func RunMigrateRedo(conn *sql.DB) error {
+	return goose.Redo(conn, ".")
+}
+
+func RunMigrateStatus(conn *sql.DB) error {
+	return goose.Status(conn, ".")
+}
+
+func GetVersion(conn *sql.DB) (int64, error) {
+	return goose.GetDBVersion(conn)
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/nanoteck137/pyrin/ember"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+type Notification struct {
+	RowId int `db:"rowid"`
+
+	Id string `db:"id"`
+
+	Type types.NotificationType `db:"type"`
+
+	Title   string `db:"title"`
+	Message string `db:"message"`
+
+	CollectionId sql.NullString `db:"collection_id"`
+	JobId        sql.NullString `db:"job_id"`
+
+	Read sql.NullInt64 `db:"read"`
+
+	Created int64 `db:"created"`
+	Updated int64 `db:"updated"`
+}
+
+// TODO(patrik): Use goqu.T more
+func NotificationQuery() *goqu.SelectDataset {
+	query := dialect.From("notifications").
+		Select(
+			"notifications.rowid",
+
+			"notifications.id",
+
+			"notifications.type",
+
+			"notifications.title",
+			"notifications.message",
+
+			"notifications.collection_id",
+			"notifications.job_id",
+
+			"notifications.read",
+
+			"notifications.created",
+			"notifications.updated",
+		)
+
+	return query
+}
+
+type NotificationFilter struct {
+	OnlyUnread bool
+}
+
+func (db DB) GetPagedNotifications(ctx context.Context, filter NotificationFilter, opts FetchOptions) ([]Notification, types.Page, error) {
+	query := NotificationQuery().
+		Order(goqu.I("notifications.created").Desc())
+
+	if filter.OnlyUnread {
+		query = query.Where(goqu.I("notifications.read").IsNull())
+	}
+
+	countQuery := query.
+		Select(goqu.COUNT("notifications.id"))
+
+	if opts.PerPage > 0 {
+		query = query.
+			Limit(uint(opts.PerPage)).
+			Offset(uint(opts.Page * opts.PerPage))
+	}
+
+	totalItems, err := ember.Single[int](db.db, ctx, countQuery)
+	if err != nil {
+		return nil, types.Page{}, err
+	}
+
+	totalPages := utils.TotalPages(opts.PerPage, totalItems)
+	page := types.Page{
+		Page:       opts.Page,
+		PerPage:    opts.PerPage,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}
+
+	items, err := ember.Multiple[Notification](db.db, ctx, query)
+	if err != nil {
+		return nil, types.Page{}, err
+	}
+
+	return items, page, nil
+}
+
+func (db DB) GetUnreadNotificationCount(ctx context.Context) (int, error) {
+	query := dialect.From("notifications").
+		Select(goqu.COUNT("notifications.id")).
+		Where(goqu.I("notifications.read").IsNull())
+
+	return ember.Single[int](db.db, ctx, query)
+}
+
+func (db DB) GetNotificationById(ctx context.Context, id string) (Notification, error) {
+	query := NotificationQuery().
+		Where(goqu.I("notifications.id").Eq(id))
+
+	return ember.Single[Notification](db.db, ctx, query)
+}
+
+type CreateNotificationParams struct {
+	Id string
+
+	Type types.NotificationType
+
+	Title   string
+	Message string
+
+	CollectionId sql.NullString
+	JobId        sql.NullString
+
+	Created int64
+	Updated int64
+}
+
+func (db DB) CreateNotification(ctx context.Context, params CreateNotificationParams) (string, error) {
+	t := time.Now().UnixMilli()
+	created := params.Created
+	updated := params.Updated
+
+	if created == 0 && updated == 0 {
+		created = t
+		updated = t
+	}
+
+	id := params.Id
+	if id == "" {
+		id = utils.CreateNotificationId()
+	}
+
+	query := dialect.Insert("notifications").Rows(goqu.Record{
+		"id": id,
+
+		"type": params.Type,
+
+		"title":   params.Title,
+		"message": params.Message,
+
+		"collection_id": params.CollectionId,
+		"job_id":        params.JobId,
+
+		"created": created,
+		"updated": updated,
+	}).
+		Returning("id")
+
+	return ember.Single[string](db.db, ctx, query)
+}
+
+type NotificationChanges struct {
+	Read Change[sql.NullInt64]
+}
+
+func (db DB) UpdateNotification(ctx context.Context, id string, changes NotificationChanges) error {
+	record := goqu.Record{}
+
+	addToRecord(record, "read", changes.Read)
+
+	if len(record) == 0 {
+		return nil
+	}
+
+	record["updated"] = time.Now().UnixMilli()
+
+	query := dialect.Update("notifications").
+		Set(record).
+		Where(goqu.I("notifications.id").Eq(id))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db DB) MarkAllNotificationsRead(ctx context.Context) error {
+	t := time.Now().UnixMilli()
+
+	query := dialect.Update("notifications").
+		Set(goqu.Record{
+			"read":    t,
+			"updated": t,
+		}).
+		Where(goqu.I("notifications.read").IsNull())
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db DB) RemoveNotification(ctx context.Context, id string) error {
+	query := dialect.Delete("notifications").
+		Where(goqu.I("notifications.id").Eq(id))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
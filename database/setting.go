@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/nanoteck137/pyrin/ember"
+)
+
+type Setting struct {
+	Key   string `db:"key"`
+	Value string `db:"value"`
+
+	Updated int64 `db:"updated"`
+}
+
+func SettingQuery() *goqu.SelectDataset {
+	query := dialect.From("settings").
+		Select(
+			"settings.key",
+			"settings.value",
+
+			"settings.updated",
+		)
+
+	return query
+}
+
+func (db DB) GetAllSettings(ctx context.Context) ([]Setting, error) {
+	query := SettingQuery().
+		Order(goqu.I("settings.key").Asc())
+
+	return ember.Multiple[Setting](db.db, ctx, query)
+}
+
+func (db DB) GetSetting(ctx context.Context, key string) (Setting, error) {
+	query := SettingQuery().
+		Where(goqu.I("settings.key").Eq(key))
+
+	return ember.Single[Setting](db.db, ctx, query)
+}
+
+func (db DB) SetSetting(ctx context.Context, key, value string) error {
+	updated := time.Now().UnixMilli()
+
+	query := dialect.Insert("settings").Rows(goqu.Record{
+		"key":     key,
+		"value":   value,
+		"updated": updated,
+	}).
+		OnConflict(goqu.DoUpdate("key", goqu.Record{
+			"value":   value,
+			"updated": updated,
+		}))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (db DB) RemoveSetting(ctx context.Context, key string) error {
+	query := dialect.Delete("settings").
+		Where(goqu.I("settings.key").Eq(key))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/nanoteck137/pyrin/ember"
+	"github.com/nanoteck137/storebook/types"
+	"github.com/nanoteck137/storebook/utils"
+)
+
+type TaskRun struct {
+	RowId int `db:"rowid"`
+
+	Id string `db:"id"`
+
+	Task   string          `db:"task"`
+	Status types.JobStatus `db:"status"`
+	Error  sql.NullString  `db:"error"`
+
+	Started  int64         `db:"started"`
+	Finished sql.NullInt64 `db:"finished"`
+
+	Created int64 `db:"created"`
+	Updated int64 `db:"updated"`
+}
+
+// TODO(patrik): Use goqu.T more
+func TaskRunQuery() *goqu.SelectDataset {
+	query := dialect.From("task_runs").
+		Select(
+			"task_runs.rowid",
+
+			"task_runs.id",
+
+			"task_runs.task",
+			"task_runs.status",
+			"task_runs.error",
+
+			"task_runs.started",
+			"task_runs.finished",
+
+			"task_runs.created",
+			"task_runs.updated",
+		)
+
+	return query
+}
+
+func (db DB) GetLastTaskRun(ctx context.Context, task string) (TaskRun, error) {
+	query := TaskRunQuery().
+		Where(goqu.I("task_runs.task").Eq(task)).
+		Order(goqu.I("task_runs.started").Desc()).
+		Limit(1)
+
+	return ember.Single[TaskRun](db.db, ctx, query)
+}
+
+func (db DB) GetTaskRuns(ctx context.Context, task string, limit int) ([]TaskRun, error) {
+	query := TaskRunQuery().
+		Where(goqu.I("task_runs.task").Eq(task)).
+		Order(goqu.I("task_runs.started").Desc()).
+		Limit(uint(limit))
+
+	return ember.Multiple[TaskRun](db.db, ctx, query)
+}
+
+type CreateTaskRunParams struct {
+	Id string
+
+	Task   string
+	Status types.JobStatus
+
+	Started int64
+
+	Created int64
+	Updated int64
+}
+
+func (db DB) CreateTaskRun(ctx context.Context, params CreateTaskRunParams) (string, error) {
+	t := time.Now().UnixMilli()
+	created := params.Created
+	updated := params.Updated
+
+	if created == 0 && updated == 0 {
+		created = t
+		updated = t
+	}
+
+	id := params.Id
+	if id == "" {
+		id = utils.CreateTaskRunId()
+	}
+
+	started := params.Started
+	if started == 0 {
+		started = t
+	}
+
+	query := dialect.Insert("task_runs").Rows(goqu.Record{
+		"id": id,
+
+		"task":   params.Task,
+		"status": params.Status,
+
+		"started": started,
+
+		"created": created,
+		"updated": updated,
+	}).
+		Returning("id")
+
+	return ember.Single[string](db.db, ctx, query)
+}
+
+type TaskRunChanges struct {
+	Status Change[types.JobStatus]
+	Error  Change[sql.NullString]
+
+	Finished Change[sql.NullInt64]
+}
+
+func (db DB) UpdateTaskRun(ctx context.Context, id string, changes TaskRunChanges) error {
+	record := goqu.Record{}
+
+	addToRecord(record, "status", changes.Status)
+	addToRecord(record, "error", changes.Error)
+
+	addToRecord(record, "finished", changes.Finished)
+
+	if len(record) == 0 {
+		return nil
+	}
+
+	record["updated"] = time.Now().UnixMilli()
+
+	query := dialect.Update("task_runs").
+		Set(record).
+		Where(goqu.I("task_runs.id").Eq(id))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NOTE(patrik): Removes all but the newest runs for the task
+func (db DB) TrimTaskRuns(ctx context.Context, task string, keep int) error {
+	newest := dialect.From("task_runs").
+		Select("task_runs.id").
+		Where(goqu.I("task_runs.task").Eq(task)).
+		Order(goqu.I("task_runs.started").Desc()).
+		Limit(uint(keep))
+
+	query := dialect.Delete("task_runs").
+		Where(
+			goqu.I("task_runs.task").Eq(task),
+			goqu.I("task_runs.id").NotIn(newest),
+		)
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// NOTE(patrik): Marks the runs that was interrupted by a shutdown as failed
+func (db DB) FailRunningTaskRuns(ctx context.Context) error {
+	t := time.Now().UnixMilli()
+
+	query := dialect.Update("task_runs").
+		Set(goqu.Record{
+			"status":   types.JobStatusFailed,
+			"error":    "interrupted",
+			"finished": t,
+			"updated":  t,
+		}).
+		Where(goqu.I("task_runs.status").Eq(types.JobStatusRunning))
+
+	_, err := db.db.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
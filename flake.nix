@@ -27,14 +27,14 @@
           pname = "storebook";
           version = fullVersion;
           src = ./.;
-          subPackages = ["cmd/storebook"];
+          subPackages = ["cmd/storebook" "cmd/storebook-cli"];
 
           ldflags = [
             "-X github.com/nanoteck137/storebook.Version=${version}"
             "-X github.com/nanoteck137/storebook.Commit=${self.dirtyRev or self.rev or "no-commit"}"
           ];
 
-          vendorHash = "sha256-HNoT1fNN2s9+kZsG3+ObGoYolz758rE1srHFDwBGe0E=";
+          vendorHash = "sha256-5tEWl95VP+ahFbrjIf7lnX4a04yy6gUQoy0d8IBt44g=";
         };
 
         frontend = pkgs.buildNpmPackage {
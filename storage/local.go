@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var _ Storage = (*Local)(nil)
+
+// Local stores the objects as files inside a directory
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) *Local {
+	return &Local{
+		root: root,
+	}
+}
+
+func (l *Local) path(key string) string {
+	return path.Join(l.root, path.Clean("/"+key))
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	p := l.path(key)
+
+	err := os.MkdirAll(path.Dir(p), 0755)
+	if err != nil {
+		return err
+	}
+
+	// NOTE(patrik): Write to a temporary file first so a half written
+	// object never shows up under the real name
+	tmp, err := os.CreateTemp(path.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadSeekCloser, Object, error) {
+	f, err := os.Open(l.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, Object{}, ErrNotFound
+		}
+
+		return nil, Object{}, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, Object{}, err
+	}
+
+	return f, Object{
+		Key:     key,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, _, err := l.Open(ctx, key)
+	return f, err
+}
+
+type sectionReadCloser struct {
+	*io.SectionReader
+	f *os.File
+}
+
+func (s sectionReadCloser) Close() error {
+	return s.f.Close()
+}
+
+func (l *Local) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(l.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return sectionReadCloser{
+		SectionReader: io.NewSectionReader(f, offset, length),
+		f:             f,
+	}, nil
+}
+
+func (l *Local) Stat(ctx context.Context, key string) (Object, error) {
+	info, err := os.Stat(l.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Object{}, ErrNotFound
+		}
+
+		return Object{}, err
+	}
+
+	return Object{
+		Key:     key,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}, nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	err := os.Remove(l.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *Local) List(ctx context.Context, prefix string) ([]Object, error) {
+	var res []Object
+
+	err := filepath.WalkDir(l.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == l.root {
+				return fs.SkipAll
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		key, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+
+		key = filepath.ToSlash(key)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		res = append(res, Object{
+			Key:     key,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
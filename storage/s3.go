@@ -0,0 +1,443 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ Storage = (*S3)(nil)
+var _ Presigner = (*S3)(nil)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3Service         = "s3"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// NOTE(patrik): sha256 of an empty body
+const s3EmptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+type S3Options struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+
+	// NOTE(patrik): Use http://endpoint/bucket/key instead of
+	// http://bucket.endpoint/key, needed by most self hosted servers
+	PathStyle bool
+
+	// NOTE(patrik): How long presigned URLs are valid, 0 disables presigning
+	PresignExpiry time.Duration
+
+	Client *http.Client
+}
+
+// S3 is a minimal client for S3 compatible object storage using signature
+// version 4
+type S3 struct {
+	opts     S3Options
+	endpoint *url.URL
+	client   *http.Client
+
+	now func() time.Time
+}
+
+func NewS3(opts S3Options) (*S3, error) {
+	endpoint, err := url.Parse(opts.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint: %w", err)
+	}
+
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint '%s'", opts.Endpoint)
+	}
+
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &S3{
+		opts:     opts,
+		endpoint: endpoint,
+		client:   client,
+		now:      time.Now,
+	}, nil
+}
+
+// s3Escape encodes the string the way SigV4 expects (RFC 3986), slashes
+// are kept when encoding paths
+func s3Escape(s string, path bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (path && c == '/') {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+
+		for _, v := range values {
+			parts = append(parts, s3Escape(k, false)+"="+s3Escape(v, false))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data string) string {
+	h := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(h[:])
+}
+
+func (s *S3) scope(t time.Time) string {
+	return t.Format(s3DateFormat) + "/" + s.opts.Region + "/" + s3Service + "/aws4_request"
+}
+
+func (s *S3) signature(t time.Time, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		t.Format(s3TimeFormat),
+		s.scope(t),
+		sha256Hex(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretKey), t.Format(s3DateFormat))
+	key = hmacSHA256(key, s.opts.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+
+	if s.opts.PathStyle {
+		u.Path = base + "/" + s.opts.Bucket
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+		u.Path = base
+	}
+
+	if key != "" {
+		u.Path += "/" + key
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	return &u
+}
+
+// sign adds the authorization header to the request, the host, range and
+// x-amz-* headers are signed
+func (s *S3) sign(req *http.Request, payloadHash string, t time.Time) {
+	req.Header.Set("X-Amz-Date", t.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+
+	for k, v := range req.Header {
+		name := strings.ToLower(k)
+		if name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path, true),
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.opts.AccessKey, s.scope(t), signedHeaders,
+		s.signature(t, canonicalRequest),
+	))
+}
+
+func (s *S3) presign(method string, u *url.URL, expires time.Duration, t time.Time) string {
+	query := u.Query()
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.opts.AccessKey+"/"+s.scope(t))
+	query.Set("X-Amz-Date", t.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3Escape(u.Path, true),
+		s3CanonicalQuery(query),
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(t, canonicalRequest))
+
+	res := *u
+	res.RawQuery = s3CanonicalQuery(query)
+
+	return res.String()
+}
+
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (s *S3) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	payloadHash := s3EmptyPayload
+	if body != nil {
+		req.ContentLength = size
+		payloadHash = s3UnsignedPayload
+	}
+
+	s.sign(req, payloadHash, s.now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	var e s3Error
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if xml.Unmarshal(data, &e) == nil && e.Code != "" {
+		return nil, fmt.Errorf("storage: s3 %s %s: %s: %s", method, u.Path, e.Code, e.Message)
+	}
+
+	return nil, fmt.Errorf("storage: s3 %s %s: %s", method, u.Path, resp.Status)
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+
+	if r == nil {
+		r = strings.NewReader("")
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key), r, size, header)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key), nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if length <= 0 {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key), nil, 0, header)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (Object, error) {
+	resp, err := s.do(ctx, http.MethodHead, s.objectURL(key), nil, 0, nil)
+	if err != nil {
+		return Object{}, err
+	}
+	resp.Body.Close()
+
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+
+	return Object{
+		Key:     key,
+		Size:    resp.ContentLength,
+		ModTime: modTime,
+	}, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key), nil, 0, nil)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	var res []Object
+
+	token := ""
+	for {
+		u := s.objectURL("")
+		query := url.Values{}
+		query.Set("list-type", "2")
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		u.RawQuery = s3CanonicalQuery(query)
+
+		resp, err := s.do(ctx, http.MethodGet, u, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var list s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range list.Contents {
+			modTime, _ := time.Parse(time.RFC3339, c.LastModified)
+
+			res = append(res, Object{
+				Key:     c.Key,
+				Size:    c.Size,
+				ModTime: modTime,
+			})
+		}
+
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			break
+		}
+
+		token = list.NextContinuationToken
+	}
+
+	return res, nil
+}
+
+func (s *S3) PresignGet(ctx context.Context, key, contentType string) (string, error) {
+	if s.opts.PresignExpiry <= 0 {
+		return "", ErrPresignDisabled
+	}
+
+	u := s.objectURL(key)
+
+	// NOTE(patrik): The objects are stored without extensions so tell S3
+	// which content type to respond with
+	if contentType != "" {
+		query := url.Values{}
+		query.Set("response-content-type", contentType)
+		u.RawQuery = query.Encode()
+	}
+
+	return s.presign(http.MethodGet, u, s.opts.PresignExpiry, s.now().UTC()), nil
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("storage: object not found")
+var ErrPresignDisabled = errors.New("storage: presigning is disabled")
+
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage is the backend used to store the blobs, keys are slash separated
+// paths relative to the root of the backend
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// NOTE(patrik): Reads length bytes starting at offset
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (Object, error)
+	// NOTE(patrik): Deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Presigner is implemented by the backends that can hand out URLs so the
+// client can fetch the object directly from the backend
+type Presigner interface {
+	PresignGet(ctx context.Context, key, contentType string) (string, error)
+}
+
+type opener interface {
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, Object, error)
+}
+
+// Open returns a seekable reader for the object, backends without native
+// support for seeking uses range reads
+func Open(ctx context.Context, s Storage, key string) (io.ReadSeekCloser, Object, error) {
+	if o, ok := s.(opener); ok {
+		return o.Open(ctx, key)
+	}
+
+	obj, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, Object{}, err
+	}
+
+	return &rangeReader{
+		ctx:  ctx,
+		s:    s,
+		key:  key,
+		size: obj.Size,
+	}, obj, nil
+}
+
+func Exists(ctx context.Context, s Storage, key string) (bool, error) {
+	_, err := s.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+type rangeReader struct {
+	ctx  context.Context
+	s    Storage
+	key  string
+	size int64
+
+	offset int64
+	body   io.ReadCloser
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if r.body == nil {
+		body, err := r.s.GetRange(r.ctx, r.key, r.offset, r.size-r.offset)
+		if err != nil {
+			return 0, err
+		}
+
+		r.body = body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	var next int64
+	switch whence {
+	case io.SeekStart:
+		next = offset
+	case io.SeekCurrent:
+		next = r.offset + offset
+	case io.SeekEnd:
+		next = r.size + offset
+	default:
+		return 0, errors.New("storage: invalid whence")
+	}
+
+	if next < 0 {
+		return 0, errors.New("storage: negative position")
+	}
+
+	if next != r.offset && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+
+	r.offset = next
+
+	return next, nil
+}
+
+func (r *rangeReader) Close() error {
+	if r.body != nil {
+		return r.body.Close()
+	}
+
+	return nil
+}
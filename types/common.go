@@ -20,6 +20,22 @@ func (d WorkDir) CollectionsDir() string {
 	return path.Join(d.String(), "collections")
 }
 
+func (d WorkDir) UploadsDir() string {
+	return path.Join(d.String(), "uploads")
+}
+
+func (d WorkDir) ExportsDir() string {
+	return path.Join(d.String(), "exports")
+}
+
+func (d WorkDir) BlobsDir() string {
+	return path.Join(d.String(), "blobs")
+}
+
+func (d WorkDir) BackupsDir() string {
+	return path.Join(d.String(), "backups")
+}
+
 func (d WorkDir) CollectionDirById(id string) CollectionDir {
 	return CollectionDir(path.Join(d.CollectionsDir(), id))
 }
@@ -30,14 +46,20 @@ func (d CollectionDir) String() string {
 	return string(d)
 }
 
+// NOTE(patrik): Legacy location of the image files, the files are moved
+// into the blob store on startup
 func (d CollectionDir) Images() string {
 	return path.Join(d.String(), "images")
 }
 
+func (d CollectionDir) Thumbnails() string {
+	return path.Join(d.String(), "thumbnails")
+}
+
 func (d CollectionDir) Create() error {
 	dirs := []string{
 		d.String(),
-		d.Images(),
+		d.Thumbnails(),
 	}
 
 	for _, dir := range dirs {
@@ -0,0 +1,20 @@
+package types
+
+type HashAlgorithm string
+
+const (
+	// NOTE(patrik): Only used by old images, new images are hashed with the
+	// algorithm from the config
+	HashAlgorithmMD5    HashAlgorithm = "md5"
+	HashAlgorithmSHA256 HashAlgorithm = "sha256"
+)
+
+func IsValidHashAlgorithm(a HashAlgorithm) bool {
+	switch a {
+	case HashAlgorithmMD5,
+		HashAlgorithmSHA256:
+		return true
+	}
+
+	return false
+}
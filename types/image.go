@@ -0,0 +1,18 @@
+package types
+
+type ColorMode string
+
+const (
+	ColorModeGray     ColorMode = "gray"
+	ColorModeRGB      ColorMode = "rgb"
+	ColorModeRGBA     ColorMode = "rgba"
+	ColorModeCMYK     ColorMode = "cmyk"
+	ColorModePaletted ColorMode = "paletted"
+)
+
+type SpreadSide string
+
+const (
+	SpreadSideLeft  SpreadSide = "left"
+	SpreadSideRight SpreadSide = "right"
+)
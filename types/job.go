@@ -5,18 +5,24 @@ import "errors"
 type JobStatus string
 
 const (
-	JobStatusQueued JobStatus = "queued"
-	JobStatusRunning JobStatus = "running"
-	JobStatusSuccess JobStatus = "success"
-	JobStatusFailed  JobStatus = "failed"
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSuccess   JobStatus = "success"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
 )
 
+func (s JobStatus) IsDone() bool {
+	return s == JobStatusSuccess || s == JobStatusFailed || s == JobStatusCancelled
+}
+
 func IsValidJobStatus(t JobStatus) bool {
 	switch t {
 	case JobStatusQueued,
 		JobStatusRunning,
 		JobStatusSuccess,
-		JobStatusFailed:
+		JobStatusFailed,
+		JobStatusCancelled:
 		return true
 	}
 
@@ -8,13 +8,16 @@ import (
 
 const MediaDateLayout = "2006-01-02"
 
-// TODO(patrik): Change to take in time.Time
 func GetAiringSeason(d string) string {
 	t, err := time.Parse(MediaDateLayout, d)
 	if err != nil {
 		return ""
 	}
 
+	return GetAiringSeasonFromTime(t)
+}
+
+func GetAiringSeasonFromTime(t time.Time) string {
 	year := t.Year()
 
 	switch t.Month() {
@@ -25,7 +28,7 @@ func GetAiringSeason(d string) string {
 	case time.July, time.August, time.September:
 		return "summer-" + strconv.Itoa(year)
 	case time.October, time.November, time.December:
-		return "winter-" + strconv.Itoa(year)
+		return "fall-" + strconv.Itoa(year)
 	}
 
 	return ""
@@ -0,0 +1,105 @@
+package types
+
+import "errors"
+
+type ReadingDirection string
+
+const (
+	ReadingDirectionLTR ReadingDirection = "ltr"
+	ReadingDirectionRTL ReadingDirection = "rtl"
+
+	// NOTE(patrik): Top to bottom, used for webtoons
+	ReadingDirectionTTB ReadingDirection = "ttb"
+)
+
+func IsValidReadingDirection(d ReadingDirection) bool {
+	switch d {
+	case ReadingDirectionLTR,
+		ReadingDirectionRTL,
+		ReadingDirectionTTB:
+		return true
+	}
+
+	return false
+}
+
+type ReaderLayout string
+
+const (
+	ReaderLayoutSingle     ReaderLayout = "single"
+	ReaderLayoutDouble     ReaderLayout = "double"
+	ReaderLayoutContinuous ReaderLayout = "continuous"
+)
+
+func IsValidReaderLayout(l ReaderLayout) bool {
+	switch l {
+	case ReaderLayoutSingle,
+		ReaderLayoutDouble,
+		ReaderLayoutContinuous:
+		return true
+	}
+
+	return false
+}
+
+type ReaderFit string
+
+const (
+	ReaderFitScreen   ReaderFit = "screen"
+	ReaderFitWidth    ReaderFit = "width"
+	ReaderFitHeight   ReaderFit = "height"
+	ReaderFitOriginal ReaderFit = "original"
+)
+
+func IsValidReaderFit(f ReaderFit) bool {
+	switch f {
+	case ReaderFitScreen,
+		ReaderFitWidth,
+		ReaderFitHeight,
+		ReaderFitOriginal:
+		return true
+	}
+
+	return false
+}
+
+// NOTE(patrik): Empty strings are allowed by the validators, they are
+// used to clear an override
+func validateReaderValue(val any, isValid func(s string) bool, message string) error {
+	if p, ok := val.(*string); ok {
+		if p == nil {
+			return nil
+		}
+
+		val = *p
+	}
+
+	s, ok := val.(string)
+	if !ok {
+		return errors.New("expected string")
+	}
+
+	if s != "" && !isValid(s) {
+		return errors.New(message)
+	}
+
+	return nil
+}
+
+func ValidateReadingDirection(val any) error {
+	return validateReaderValue(val, func(s string) bool {
+		return IsValidReadingDirection(ReadingDirection(s))
+	}, "invalid reading direction")
+}
+
+func ValidateReaderLayout(val any) error {
+	return validateReaderValue(val, func(s string) bool {
+		return IsValidReaderLayout(ReaderLayout(s))
+	}, "invalid reader layout")
+}
+
+func ValidateReaderFit(val any) error {
+	return validateReaderValue(val, func(s string) bool {
+		return IsValidReaderFit(ReaderFit(s))
+	}, "invalid reader fit")
+}
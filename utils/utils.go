@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"cmp"
 	"crypto/md5"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"log"
 	"math"
@@ -17,6 +20,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 	"unicode"
 
@@ -31,7 +35,10 @@ var CreateId = createIdGenerator(32)
 var CreateSmallId = createIdGenerator(8)
 
 var CreateCollectionId = createIdGenerator(8)
-var CreateImageId = createIdGenerator(5)
+var CreateImageId = createIdGenerator(8)
+var CreateJobId = createIdGenerator(16)
+var CreateNotificationId = createIdGenerator(16)
+var CreateTaskRunId = createIdGenerator(16)
 
 var CreateUserId = createIdGenerator(8)
 var CreateApiTokenId = createIdGenerator(32)
@@ -157,6 +164,14 @@ func SqlNullToFloat64Ptr(value sql.NullFloat64) *float64 {
 	return nil
 }
 
+func SqlNullToBoolPtr(value sql.NullBool) *bool {
+	if value.Valid {
+		return &value.Bool
+	}
+
+	return nil
+}
+
 func Min[T cmp.Ordered](value T, min T) T {
 	if value < min {
 		return min
@@ -234,6 +249,23 @@ func CopyFile(src, dst string) (int64, error) {
 	return nBytes, err
 }
 
+// MoveFile renames the file and falls back to copying when the destination
+// is on another filesystem
+func MoveFile(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	_, err = CopyFile(src, dst)
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	return os.Remove(src)
+}
+
 func RoundFloat(val float64, precision uint) float64 {
 	ratio := math.Pow(10, float64(precision))
 	return math.Round(val*ratio) / ratio
@@ -307,11 +339,20 @@ func ImageExtToContentType(ext string) (string, error) {
 		return "image/png", nil
 	case ".jpg", ".jpeg":
 		return "image/jpeg", nil
+	case ".gif":
+		return "image/gif", nil
+	case ".webp":
+		return "image/webp", nil
 	default:
 		return "", fmt.Errorf("unsupported ext: %s", ext)
 	}
 }
 
+func IsImageExt(ext string) bool {
+	_, err := ImageExtToContentType(strings.ToLower(ext))
+	return err == nil
+}
+
 func GetImageExtFromContentType(contentType string) (string, error) {
 	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
@@ -362,9 +403,39 @@ func DownloadImage(url, outDir, name string) (string, error) {
 	return out, nil
 }
 
-func WriteHashedFile(data []byte, outDir, ext string) (string, string, error) {
-	h := md5.Sum(data)
-	hash := hex.EncodeToString(h[:])
+func NewHasher(alg types.HashAlgorithm) (hash.Hash, error) {
+	switch alg {
+	case types.HashAlgorithmMD5:
+		return md5.New(), nil
+	case types.HashAlgorithmSHA256:
+		return sha256.New(), nil
+	}
+
+	return nil, fmt.Errorf("unknown hash algorithm: %s", alg)
+}
+
+func HashReader(r io.Reader, alg types.HashAlgorithm) (string, error) {
+	h, err := NewHasher(alg)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.Copy(h, r)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func WriteHashedFile(data []byte, outDir, ext string, alg types.HashAlgorithm) (string, string, error) {
+	h, err := NewHasher(alg)
+	if err != nil {
+		return "", "", err
+	}
+
+	h.Write(data)
+	hash := hex.EncodeToString(h.Sum(nil))
 
 	name := hash+ext
 	out := path.Join(outDir, name)
@@ -405,7 +476,7 @@ func DownloadImageHashed(url, outDir string) (string, error) {
 		return "", fmt.Errorf("failed to read body: %w", err)
 	}
 
-	out, _, err := WriteHashedFile(data, outDir, ext)
+	out, _, err := WriteHashedFile(data, outDir, ext, types.HashAlgorithmSHA256)
 	if err != nil {
 		return "", err
 	}
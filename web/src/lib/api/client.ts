@@ -11,6 +11,18 @@ export class ApiClient extends BaseApiClient {
     this.url = new ClientUrls(baseUrl);
   }
   
+  analyzeImages(options?: ExtraOptions) {
+    return this.request("/api/v1/system/analyze", "POST", api.CreateJob, z.any(), undefined, options)
+  }
+  
+  cancelJob(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/jobs/${id}/cancel`, "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  createBackup(body: api.CreateBackupBody, options?: ExtraOptions) {
+    return this.request("/api/v1/system/backups", "POST", api.CreateJob, z.any(), body, options)
+  }
+  
   createCollection(body: api.CreateCollectionBody, options?: ExtraOptions) {
     return this.request("/api/v1/collections", "POST", api.CreateCollection, z.any(), body, options)
   }
@@ -19,33 +31,180 @@ export class ApiClient extends BaseApiClient {
     return this.request(`/api/v1/collections/${id}`, "DELETE", z.undefined(), z.any(), undefined, options)
   }
   
+  deleteCollectionImage(id: string, imageId: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/images/${imageId}`, "DELETE", z.undefined(), z.any(), undefined, options)
+  }
+  
+  dismissNotification(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/notifications/${id}`, "DELETE", z.undefined(), z.any(), undefined, options)
+  }
+  
   editCollection(id: string, body: api.EditCollectionBody, options?: ExtraOptions) {
     return this.request(`/api/v1/collections/${id}`, "PATCH", z.undefined(), z.any(), body, options)
   }
   
+  editCollectionImage(id: string, imageId: string, body: api.EditCollectionImageBody, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/images/${imageId}`, "PATCH", z.undefined(), z.any(), body, options)
+  }
+  
+  exportCollection(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/export`, "POST", api.CreateJob, z.any(), undefined, options)
+  }
+  
+  generateCollectionThumbnails(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/thumbnails`, "POST", api.CreateJob, z.any(), undefined, options)
+  }
+  
+  getBackups(options?: ExtraOptions) {
+    return this.request("/api/v1/system/backups", "GET", api.GetBackups, z.any(), undefined, options)
+  }
+  
+  getCalendar(options?: ExtraOptions) {
+    return this.request("/api/v1/calendar", "GET", api.GetCalendar, z.any(), undefined, options)
+  }
+  
   getCollectionById(id: string, options?: ExtraOptions) {
     return this.request(`/api/v1/collections/${id}`, "GET", api.GetCollectionById, z.any(), undefined, options)
   }
   
   
+  getCollectionDuplicates(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/duplicates`, "GET", api.GetCollectionDuplicates, z.any(), undefined, options)
+  }
+  
+  
   getCollectionImages(id: string, options?: ExtraOptions) {
     return this.request(`/api/v1/collections/${id}/images`, "GET", api.GetCollectionImages, z.any(), undefined, options)
   }
   
+  
   getCollections(options?: ExtraOptions) {
     return this.request("/api/v1/collections", "GET", api.GetCollection, z.any(), undefined, options)
   }
   
+  getDuplicateCollections(options?: ExtraOptions) {
+    return this.request("/api/v1/duplicates/collections", "GET", api.GetDuplicateCollections, z.any(), undefined, options)
+  }
+  
+  
+  getJobById(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/jobs/${id}`, "GET", api.GetJobById, z.any(), undefined, options)
+  }
+  
+  getJobs(options?: ExtraOptions) {
+    return this.request("/api/v1/jobs", "GET", api.GetJobs, z.any(), undefined, options)
+  }
+  
+  getNotifications(options?: ExtraOptions) {
+    return this.request("/api/v1/notifications", "GET", api.GetNotifications, z.any(), undefined, options)
+  }
+  
+  getReaderDefaults(options?: ExtraOptions) {
+    return this.request("/api/v1/reader/defaults", "GET", api.GetReaderDefaults, z.any(), undefined, options)
+  }
+  
+  getStorageStats(options?: ExtraOptions) {
+    return this.request("/api/v1/system/storage", "GET", api.GetStorageStats, z.any(), undefined, options)
+  }
+  
   getSystemInfo(options?: ExtraOptions) {
     return this.request("/api/v1/system/info", "GET", api.GetSystemInfo, z.any(), undefined, options)
   }
   
+  getTasks(options?: ExtraOptions) {
+    return this.request("/api/v1/system/tasks", "GET", api.GetTasks, z.any(), undefined, options)
+  }
+  
+  getTrash(options?: ExtraOptions) {
+    return this.request("/api/v1/trash", "GET", api.GetTrash, z.any(), undefined, options)
+  }
+  
+  markAllNotificationsRead(options?: ExtraOptions) {
+    return this.request("/api/v1/notifications/read", "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  markNotificationRead(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/notifications/${id}/read`, "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  markNotificationUnread(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/notifications/${id}/unread`, "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  
+  
+  
+  
+  
+  
+  
+  
+  
+  
+  
+  purgeCollection(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/trash/${id}`, "DELETE", z.undefined(), z.any(), undefined, options)
+  }
+  
+  rehashImages(options?: ExtraOptions) {
+    return this.request("/api/v1/system/rehash", "POST", api.CreateJob, z.any(), undefined, options)
+  }
+  
+  replaceCollectionImage(id: string, imageId: string, body: FormData, options?: ExtraOptions) {
+    return this.requestForm(`/api/v1/collections/${id}/images/${imageId}`, "PUT", z.undefined(), z.any(), body, options)
+  }
+  
+  resetCollectionCover(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/cover`, "DELETE", z.undefined(), z.any(), undefined, options)
+  }
+  
+  restoreCollection(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/trash/${id}/restore`, "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  retryJob(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/jobs/${id}/retry`, "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  runGc(body: api.RunGCBody, options?: ExtraOptions) {
+    return this.request("/api/v1/system/gc", "POST", api.RunGC, z.any(), body, options)
+  }
+  
+  runTask(name: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/system/tasks/${name}/run`, "POST", z.undefined(), z.any(), undefined, options)
+  }
+  
+  setCollectionCover(id: string, body: api.SetCollectionCoverBody, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/cover`, "PATCH", z.undefined(), z.any(), body, options)
+  }
+  
   signin(body: api.SigninBody, options?: ExtraOptions) {
     return this.request("/api/v1/auth/signin", "POST", api.Signin, z.any(), body, options)
   }
   
+  splitCollectionImage(id: string, imageId: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/images/${imageId}/split`, "POST", api.SplitCollectionImage, z.any(), undefined, options)
+  }
+  
+  splitCollectionSpreads(id: string, options?: ExtraOptions) {
+    return this.request(`/api/v1/collections/${id}/spreads/split`, "POST", api.CreateJob, z.any(), undefined, options)
+  }
+  
+  
+  updateReaderDefaults(body: api.UpdateReaderDefaultsBody, options?: ExtraOptions) {
+    return this.request("/api/v1/reader/defaults", "PATCH", z.undefined(), z.any(), body, options)
+  }
+  
+  uploadCollectionCover(id: string, body: FormData, options?: ExtraOptions) {
+    return this.requestForm(`/api/v1/collections/${id}/cover`, "POST", z.undefined(), z.any(), body, options)
+  }
+  
   uploadToCollection(id: string, body: FormData, options?: ExtraOptions) {
-    return this.requestForm(`/api/v1/collections/${id}/upload`, "POST", z.undefined(), z.any(), body, options)
+    return this.requestForm(`/api/v1/collections/${id}/upload`, "POST", api.UploadToCollection, z.any(), body, options)
+  }
+  
+  verifyImages(body: api.VerifyImagesBody, options?: ExtraOptions) {
+    return this.request("/api/v1/system/verify", "POST", api.CreateJob, z.any(), body, options)
   }
 }
 
@@ -56,6 +215,18 @@ export class ClientUrls {
     this.baseUrl = baseUrl;
   }
   
+  analyzeImages() {
+    return createUrl(this.baseUrl, "/api/v1/system/analyze")
+  }
+  
+  cancelJob(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/jobs/${id}/cancel`)
+  }
+  
+  createBackup() {
+    return createUrl(this.baseUrl, "/api/v1/system/backups")
+  }
+  
   createCollection() {
     return createUrl(this.baseUrl, "/api/v1/collections")
   }
@@ -64,14 +235,50 @@ export class ClientUrls {
     return createUrl(this.baseUrl, `/api/v1/collections/${id}`)
   }
   
+  deleteCollectionImage(id: string, imageId: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/images/${imageId}`)
+  }
+  
+  dismissNotification(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/notifications/${id}`)
+  }
+  
   editCollection(id: string) {
     return createUrl(this.baseUrl, `/api/v1/collections/${id}`)
   }
   
+  editCollectionImage(id: string, imageId: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/images/${imageId}`)
+  }
+  
+  exportCollection(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/export`)
+  }
+  
+  generateCollectionThumbnails(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/thumbnails`)
+  }
+  
+  getBackups() {
+    return createUrl(this.baseUrl, "/api/v1/system/backups")
+  }
+  
+  getCalendar() {
+    return createUrl(this.baseUrl, "/api/v1/calendar")
+  }
+  
   getCollectionById(id: string) {
     return createUrl(this.baseUrl, `/api/v1/collections/${id}`)
   }
   
+  getCollectionCover(id: string, file: string) {
+    return createUrl(this.baseUrl, `/files/collections/${id}/cover/${file}`)
+  }
+  
+  getCollectionDuplicates(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/duplicates`)
+  }
+  
   getCollectionImage(id: string, file: string) {
     return createUrl(this.baseUrl, `/files/collections/${id}/images/${file}`)
   }
@@ -80,19 +287,175 @@ export class ClientUrls {
     return createUrl(this.baseUrl, `/api/v1/collections/${id}/images`)
   }
   
+  getCollectionThumbnail(id: string, file: string) {
+    return createUrl(this.baseUrl, `/files/collections/${id}/thumbnails/${file}`)
+  }
+  
   getCollections() {
     return createUrl(this.baseUrl, "/api/v1/collections")
   }
   
+  getDuplicateCollections() {
+    return createUrl(this.baseUrl, "/api/v1/duplicates/collections")
+  }
+  
+  getExport(file: string) {
+    return createUrl(this.baseUrl, `/files/exports/${file}`)
+  }
+  
+  getJobById(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/jobs/${id}`)
+  }
+  
+  getJobs() {
+    return createUrl(this.baseUrl, "/api/v1/jobs")
+  }
+  
+  getNotifications() {
+    return createUrl(this.baseUrl, "/api/v1/notifications")
+  }
+  
+  getReaderDefaults() {
+    return createUrl(this.baseUrl, "/api/v1/reader/defaults")
+  }
+  
+  getStorageStats() {
+    return createUrl(this.baseUrl, "/api/v1/system/storage")
+  }
+  
   getSystemInfo() {
     return createUrl(this.baseUrl, "/api/v1/system/info")
   }
   
+  getTasks() {
+    return createUrl(this.baseUrl, "/api/v1/system/tasks")
+  }
+  
+  getTrash() {
+    return createUrl(this.baseUrl, "/api/v1/trash")
+  }
+  
+  markAllNotificationsRead() {
+    return createUrl(this.baseUrl, "/api/v1/notifications/read")
+  }
+  
+  markNotificationRead(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/notifications/${id}/read`)
+  }
+  
+  markNotificationUnread(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/notifications/${id}/unread`)
+  }
+  
+  opds2Collections() {
+    return createUrl(this.baseUrl, "/opds/v2/collections")
+  }
+  
+  opds2Manifest(id: string) {
+    return createUrl(this.baseUrl, `/opds/v2/collections/${id}/manifest.json`)
+  }
+  
+  opds2Recent() {
+    return createUrl(this.baseUrl, "/opds/v2/recent")
+  }
+  
+  opds2Root() {
+    return createUrl(this.baseUrl, "/opds/v2")
+  }
+  
+  opdsCollections() {
+    return createUrl(this.baseUrl, "/opds/collections")
+  }
+  
+  opdsDownloadCollection(id: string) {
+    return createUrl(this.baseUrl, `/opds/collections/${id}/download`)
+  }
+  
+  opdsGetPage(id: string, page: string) {
+    return createUrl(this.baseUrl, `/opds/collections/${id}/pages/${page}`)
+  }
+  
+  opdsRecent() {
+    return createUrl(this.baseUrl, "/opds/recent")
+  }
+  
+  opdsRoot() {
+    return createUrl(this.baseUrl, "/opds")
+  }
+  
+  opdsSeries() {
+    return createUrl(this.baseUrl, "/opds/series")
+  }
+  
+  opdsSeriesCollections() {
+    return createUrl(this.baseUrl, "/opds/series/collections")
+  }
+  
+  purgeCollection(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/trash/${id}`)
+  }
+  
+  rehashImages() {
+    return createUrl(this.baseUrl, "/api/v1/system/rehash")
+  }
+  
+  replaceCollectionImage(id: string, imageId: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/images/${imageId}`)
+  }
+  
+  resetCollectionCover(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/cover`)
+  }
+  
+  restoreCollection(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/trash/${id}/restore`)
+  }
+  
+  retryJob(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/jobs/${id}/retry`)
+  }
+  
+  runGc() {
+    return createUrl(this.baseUrl, "/api/v1/system/gc")
+  }
+  
+  runTask(name: string) {
+    return createUrl(this.baseUrl, `/api/v1/system/tasks/${name}/run`)
+  }
+  
+  setCollectionCover(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/cover`)
+  }
+  
   signin() {
     return createUrl(this.baseUrl, "/api/v1/auth/signin")
   }
   
+  splitCollectionImage(id: string, imageId: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/images/${imageId}/split`)
+  }
+  
+  splitCollectionSpreads(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/spreads/split`)
+  }
+  
+  sseHandler() {
+    return createUrl(this.baseUrl, "/api/v1/system/events")
+  }
+  
+  updateReaderDefaults() {
+    return createUrl(this.baseUrl, "/api/v1/reader/defaults")
+  }
+  
+  uploadCollectionCover(id: string) {
+    return createUrl(this.baseUrl, `/api/v1/collections/${id}/cover`)
+  }
+  
   uploadToCollection(id: string) {
     return createUrl(this.baseUrl, `/api/v1/collections/${id}/upload`)
   }
+  
+  verifyImages() {
+    return createUrl(this.baseUrl, "/api/v1/system/verify")
+  }
 }
@@ -1,28 +1,146 @@
 // DO NOT EDIT THIS: This file was generated by the Pyrin Typescript Generator
 import { z } from "zod";
 
+// Name: Backup
+export const Backup = z.object({
+  // Name: Backup.name
+  "name": z.string(),
+  // Name: Backup.id
+  "id": z.string(),
+  // Name: Backup.size
+  "size": z.number(),
+  // Name: Backup.incremental
+  "incremental": z.boolean(),
+  // Name: Backup.baseId
+  "baseId": z.string().nullable().optional(),
+  // Name: Backup.created
+  "created": z.number(),
+});
+export type Backup = z.infer<typeof Backup>;
+
+// Name: CalendarRelease
+export const CalendarRelease = z.object({
+  // Name: CalendarRelease.collectionId
+  "collectionId": z.string(),
+  // Name: CalendarRelease.title
+  "title": z.string(),
+  // Name: CalendarRelease.part
+  "part": z.number(),
+  // Name: CalendarRelease.date
+  "date": z.string(),
+});
+export type CalendarRelease = z.infer<typeof CalendarRelease>;
+
+// Name: CalendarSeason
+export const CalendarSeason = z.object({
+  // Name: CalendarSeason.season
+  "season": z.string(),
+  // Name: CalendarSeason.releases
+  "releases": z.array(CalendarRelease),
+});
+export type CalendarSeason = z.infer<typeof CalendarSeason>;
+
+// Name: Images
+export const Images = z.object({
+  // Name: Images.original
+  "original": z.string(),
+  // Name: Images.small
+  "small": z.string(),
+  // Name: Images.medium
+  "medium": z.string(),
+  // Name: Images.large
+  "large": z.string(),
+});
+export type Images = z.infer<typeof Images>;
+
+// Name: CollectionRelease
+export const CollectionRelease = z.object({
+  // Name: CollectionRelease.start
+  "start": z.string(),
+  // Name: CollectionRelease.delayDays
+  "delayDays": z.number(),
+  // Name: CollectionRelease.intervalDays
+  "intervalDays": z.number(),
+  // Name: CollectionRelease.numParts
+  "numParts": z.number(),
+  // Name: CollectionRelease.status
+  "status": z.string(),
+  // Name: CollectionRelease.currentPart
+  "currentPart": z.number(),
+  // Name: CollectionRelease.nextPart
+  "nextPart": z.number().nullable().optional(),
+  // Name: CollectionRelease.nextRelease
+  "nextRelease": z.string().nullable().optional(),
+});
+export type CollectionRelease = z.infer<typeof CollectionRelease>;
+
 // Name: Collection
 export const Collection = z.object({
   // Name: Collection.id
   "id": z.string(),
   // Name: Collection.title
   "title": z.string(),
+  // Name: Collection.series
+  "series": z.string().nullable().optional(),
+  // Name: Collection.volume
+  "volume": z.number().nullable().optional(),
+  // Name: Collection.cover
+  "cover": Images.nullable().optional(),
+  // Name: Collection.coverImageId
+  "coverImageId": z.string().nullable().optional(),
+  // Name: Collection.customCover
+  "customCover": z.boolean(),
+  // Name: Collection.release
+  "release": CollectionRelease.nullable().optional(),
 });
 export type Collection = z.infer<typeof Collection>;
 
 // Name: CollectionImage
 export const CollectionImage = z.object({
+  // Name: CollectionImage.id
+  "id": z.string(),
   // Name: CollectionImage.collectionId
   "collectionId": z.string(),
   // Name: CollectionImage.hash
   "hash": z.string(),
   // Name: CollectionImage.filename
   "filename": z.string(),
+  // Name: CollectionImage.position
+  "position": z.number(),
   // Name: CollectionImage.url
   "url": z.string(),
+  // Name: CollectionImage.width
+  "width": z.number().nullable().optional(),
+  // Name: CollectionImage.height
+  "height": z.number().nullable().optional(),
+  // Name: CollectionImage.size
+  "size": z.number().nullable().optional(),
+  // Name: CollectionImage.mimeType
+  "mimeType": z.string().nullable().optional(),
+  // Name: CollectionImage.colorMode
+  "colorMode": z.string().nullable().optional(),
+  // Name: CollectionImage.animated
+  "animated": z.boolean().nullable().optional(),
+  // Name: CollectionImage.spread
+  "spread": z.boolean(),
+  // Name: CollectionImage.split
+  "split": z.boolean(),
+  // Name: CollectionImage.sourceImageId
+  "sourceImageId": z.string().nullable().optional(),
+  // Name: CollectionImage.spreadSide
+  "spreadSide": z.string().nullable().optional(),
+  // Name: CollectionImage.images
+  "images": Images,
 });
 export type CollectionImage = z.infer<typeof CollectionImage>;
 
+// Name: CreateBackupBody
+export const CreateBackupBody = z.object({
+  // Name: CreateBackupBody.incremental
+  "incremental": z.boolean(),
+});
+export type CreateBackupBody = z.infer<typeof CreateBackupBody>;
+
 // Name: CreateCollection
 export const CreateCollection = z.object({
   // Name: CreateCollection.id
@@ -37,13 +155,103 @@ export const CreateCollectionBody = z.object({
 });
 export type CreateCollectionBody = z.infer<typeof CreateCollectionBody>;
 
+// Name: CreateJob
+export const CreateJob = z.object({
+  // Name: CreateJob.jobId
+  "jobId": z.string(),
+});
+export type CreateJob = z.infer<typeof CreateJob>;
+
+// Name: DuplicateCollection
+export const DuplicateCollection = z.object({
+  // Name: DuplicateCollection.collection
+  "collection": Collection,
+  // Name: DuplicateCollection.matched
+  "matched": z.number(),
+  // Name: DuplicateCollection.total
+  "total": z.number(),
+});
+export type DuplicateCollection = z.infer<typeof DuplicateCollection>;
+
+// Name: DuplicateCollectionPair
+export const DuplicateCollectionPair = z.object({
+  // Name: DuplicateCollectionPair.a
+  "a": DuplicateCollection,
+  // Name: DuplicateCollectionPair.b
+  "b": DuplicateCollection,
+  // Name: DuplicateCollectionPair.overlap
+  "overlap": z.number(),
+});
+export type DuplicateCollectionPair = z.infer<typeof DuplicateCollectionPair>;
+
+// Name: DuplicateGroup
+export const DuplicateGroup = z.object({
+  // Name: DuplicateGroup.images
+  "images": z.array(CollectionImage),
+});
+export type DuplicateGroup = z.infer<typeof DuplicateGroup>;
+
 // Name: EditCollectionBody
 export const EditCollectionBody = z.object({
   // Name: EditCollectionBody.title
   "title": z.string().nullable().optional(),
+  // Name: EditCollectionBody.series
+  "series": z.string().nullable().optional(),
+  // Name: EditCollectionBody.volume
+  "volume": z.number().nullable().optional(),
+  // Name: EditCollectionBody.releaseStart
+  "releaseStart": z.string().nullable().optional(),
+  // Name: EditCollectionBody.releaseDelayDays
+  "releaseDelayDays": z.number().nullable().optional(),
+  // Name: EditCollectionBody.releaseIntervalDays
+  "releaseIntervalDays": z.number().nullable().optional(),
+  // Name: EditCollectionBody.releaseNumParts
+  "releaseNumParts": z.number().nullable().optional(),
+  // Name: EditCollectionBody.readerDirection
+  "readerDirection": z.string().nullable().optional(),
+  // Name: EditCollectionBody.readerLayout
+  "readerLayout": z.string().nullable().optional(),
+  // Name: EditCollectionBody.readerFit
+  "readerFit": z.string().nullable().optional(),
 });
 export type EditCollectionBody = z.infer<typeof EditCollectionBody>;
 
+// Name: EditCollectionImageBody
+export const EditCollectionImageBody = z.object({
+  // Name: EditCollectionImageBody.position
+  "position": z.number().nullable().optional(),
+});
+export type EditCollectionImageBody = z.infer<typeof EditCollectionImageBody>;
+
+// Name: GCDanglingImage
+export const GCDanglingImage = z.object({
+  // Name: GCDanglingImage.collectionId
+  "collectionId": z.string(),
+  // Name: GCDanglingImage.imageId
+  "imageId": z.string(),
+  // Name: GCDanglingImage.filename
+  "filename": z.string(),
+});
+export type GCDanglingImage = z.infer<typeof GCDanglingImage>;
+
+// Name: GetBackups
+export const GetBackups = z.object({
+  // Name: GetBackups.backups
+  "backups": z.array(Backup),
+});
+export type GetBackups = z.infer<typeof GetBackups>;
+
+// Name: GetCalendar
+export const GetCalendar = z.object({
+  // Name: GetCalendar.start
+  "start": z.string(),
+  // Name: GetCalendar.end
+  "end": z.string(),
+  // Name: GetCalendar.seasons
+  "seasons": z.array(CalendarSeason),
+});
+export type GetCalendar = z.infer<typeof GetCalendar>;
+
 // Name: Page
 export const Page = z.object({
   // Name: Page.page
@@ -66,15 +274,62 @@ export const GetCollection = z.object({
 });
 export type GetCollection = z.infer<typeof GetCollection>;
 
+// Name: ReaderSettings
+export const ReaderSettings = z.object({
+  // Name: ReaderSettings.direction
+  "direction": z.string(),
+  // Name: ReaderSettings.layout
+  "layout": z.string(),
+  // Name: ReaderSettings.fit
+  "fit": z.string(),
+});
+export type ReaderSettings = z.infer<typeof ReaderSettings>;
+
+// Name: ReaderOverrides
+export const ReaderOverrides = z.object({
+  // Name: ReaderOverrides.direction
+  "direction": z.string().nullable().optional(),
+  // Name: ReaderOverrides.layout
+  "layout": z.string().nullable().optional(),
+  // Name: ReaderOverrides.fit
+  "fit": z.string().nullable().optional(),
+});
+export type ReaderOverrides = z.infer<typeof ReaderOverrides>;
+
 // Name: GetCollectionById
 export const GetCollectionById = z.object({
   // Name: GetCollectionById.id
   "id": z.string(),
   // Name: GetCollectionById.title
   "title": z.string(),
+  // Name: GetCollectionById.series
+  "series": z.string().nullable().optional(),
+  // Name: GetCollectionById.volume
+  "volume": z.number().nullable().optional(),
+  // Name: GetCollectionById.cover
+  "cover": Images.nullable().optional(),
+  // Name: GetCollectionById.coverImageId
+  "coverImageId": z.string().nullable().optional(),
+  // Name: GetCollectionById.customCover
+  "customCover": z.boolean(),
+  // Name: GetCollectionById.release
+  "release": CollectionRelease.nullable().optional(),
+  // Name: GetCollectionById.reader
+  "reader": ReaderSettings,
+  // Name: GetCollectionById.readerOverrides
+  "readerOverrides": ReaderOverrides,
 });
 export type GetCollectionById = z.infer<typeof GetCollectionById>;
 
+// Name: GetCollectionDuplicates
+export const GetCollectionDuplicates = z.object({
+  // Name: GetCollectionDuplicates.threshold
+  "threshold": z.number(),
+  // Name: GetCollectionDuplicates.groups
+  "groups": z.array(DuplicateGroup),
+});
+export type GetCollectionDuplicates = z.infer<typeof GetCollectionDuplicates>;
+
 // Name: GetCollectionImages
 export const GetCollectionImages = z.object({
   // Name: GetCollectionImages.images
@@ -82,6 +337,159 @@ export const GetCollectionImages = z.object({
 });
 export type GetCollectionImages = z.infer<typeof GetCollectionImages>;
 
+// Name: GetDuplicateCollections
+export const GetDuplicateCollections = z.object({
+  // Name: GetDuplicateCollections.threshold
+  "threshold": z.number(),
+  // Name: GetDuplicateCollections.minOverlap
+  "minOverlap": z.number(),
+  // Name: GetDuplicateCollections.pairs
+  "pairs": z.array(DuplicateCollectionPair),
+});
+export type GetDuplicateCollections = z.infer<typeof GetDuplicateCollections>;
+
+// Name: JobLog
+export const JobLog = z.object({
+  // Name: JobLog.message
+  "message": z.string(),
+  // Name: JobLog.created
+  "created": z.number(),
+});
+export type JobLog = z.infer<typeof JobLog>;
+
+// Name: GetJobById
+export const GetJobById = z.object({
+  // Name: GetJobById.id
+  "id": z.string(),
+  // Name: GetJobById.type
+  "type": z.string(),
+  // Name: GetJobById.status
+  "status": z.string(),
+  // Name: GetJobById.payload
+  "payload": z.string(),
+  // Name: GetJobById.result
+  "result": z.string().nullable().optional(),
+  // Name: GetJobById.error
+  "error": z.string().nullable().optional(),
+  // Name: GetJobById.progressCurrent
+  "progressCurrent": z.number(),
+  // Name: GetJobById.progressTotal
+  "progressTotal": z.number(),
+  // Name: GetJobById.attempts
+  "attempts": z.number(),
+  // Name: GetJobById.started
+  "started": z.number().nullable().optional(),
+  // Name: GetJobById.finished
+  "finished": z.number().nullable().optional(),
+  // Name: GetJobById.created
+  "created": z.number(),
+  // Name: GetJobById.updated
+  "updated": z.number(),
+  // Name: GetJobById.logs
+  "logs": z.array(JobLog),
+});
+export type GetJobById = z.infer<typeof GetJobById>;
+
+// Name: Job
+export const Job = z.object({
+  // Name: Job.id
+  "id": z.string(),
+  // Name: Job.type
+  "type": z.string(),
+  // Name: Job.status
+  "status": z.string(),
+  // Name: Job.payload
+  "payload": z.string(),
+  // Name: Job.result
+  "result": z.string().nullable().optional(),
+  // Name: Job.error
+  "error": z.string().nullable().optional(),
+  // Name: Job.progressCurrent
+  "progressCurrent": z.number(),
+  // Name: Job.progressTotal
+  "progressTotal": z.number(),
+  // Name: Job.attempts
+  "attempts": z.number(),
+  // Name: Job.started
+  "started": z.number().nullable().optional(),
+  // Name: Job.finished
+  "finished": z.number().nullable().optional(),
+  // Name: Job.created
+  "created": z.number(),
+  // Name: Job.updated
+  "updated": z.number(),
+});
+export type Job = z.infer<typeof Job>;
+
+// Name: GetJobs
+export const GetJobs = z.object({
+  // Name: GetJobs.page
+  "page": Page,
+  // Name: GetJobs.jobs
+  "jobs": z.array(Job),
+});
+export type GetJobs = z.infer<typeof GetJobs>;
+
+// Name: Notification
+export const Notification = z.object({
+  // Name: Notification.id
+  "id": z.string(),
+  // Name: Notification.type
+  "type": z.string(),
+  // Name: Notification.title
+  "title": z.string(),
+  // Name: Notification.message
+  "message": z.string(),
+  // Name: Notification.collectionId
+  "collectionId": z.string().nullable().optional(),
+  // Name: Notification.jobId
+  "jobId": z.string().nullable().optional(),
+  // Name: Notification.isRead
+  "isRead": z.boolean(),
+  // Name: Notification.read
+  "read": z.number().nullable().optional(),
+  // Name: Notification.created
+  "created": z.number(),
+});
+export type Notification = z.infer<typeof Notification>;
+
+// Name: GetNotifications
+export const GetNotifications = z.object({
+  // Name: GetNotifications.page
+  "page": Page,
+  // Name: GetNotifications.unreadCount
+  "unreadCount": z.number(),
+  // Name: GetNotifications.notifications
+  "notifications": z.array(Notification),
+});
+export type GetNotifications = z.infer<typeof GetNotifications>;
+
+// Name: GetReaderDefaults
+export const GetReaderDefaults = z.object({
+  // Name: GetReaderDefaults.direction
+  "direction": z.string(),
+  // Name: GetReaderDefaults.layout
+  "layout": z.string(),
+  // Name: GetReaderDefaults.fit
+  "fit": z.string(),
+});
+export type GetReaderDefaults = z.infer<typeof GetReaderDefaults>;
+
+// Name: GetStorageStats
+export const GetStorageStats = z.object({
+  // Name: GetStorageStats.blobs
+  "blobs": z.number(),
+  // Name: GetStorageStats.references
+  "references": z.number(),
+  // Name: GetStorageStats.storedBytes
+  "storedBytes": z.number(),
+  // Name: GetStorageStats.logicalBytes
+  "logicalBytes": z.number(),
+  // Name: GetStorageStats.savedBytes
+  "savedBytes": z.number(),
+});
+export type GetStorageStats = z.infer<typeof GetStorageStats>;
+
 // Name: GetSystemInfo
 export const GetSystemInfo = z.object({
   // Name: GetSystemInfo.version
@@ -89,6 +497,108 @@ export const GetSystemInfo = z.object({
 });
 export type GetSystemInfo = z.infer<typeof GetSystemInfo>;
 
+// Name: TaskRun
+export const TaskRun = z.object({
+  // Name: TaskRun.id
+  "id": z.string(),
+  // Name: TaskRun.status
+  "status": z.string(),
+  // Name: TaskRun.error
+  "error": z.string().nullable().optional(),
+  // Name: TaskRun.started
+  "started": z.number(),
+  // Name: TaskRun.finished
+  "finished": z.number().nullable().optional(),
+});
+export type TaskRun = z.infer<typeof TaskRun>;
+
+// Name: Task
+export const Task = z.object({
+  // Name: Task.name
+  "name": z.string(),
+  // Name: Task.description
+  "description": z.string(),
+  // Name: Task.schedule
+  "schedule": z.string(),
+  // Name: Task.running
+  "running": z.boolean(),
+  // Name: Task.nextRun
+  "nextRun": z.number().nullable().optional(),
+  // Name: Task.lastRun
+  "lastRun": TaskRun.nullable().optional(),
+});
+export type Task = z.infer<typeof Task>;
+
+// Name: GetTasks
+export const GetTasks = z.object({
+  // Name: GetTasks.tasks
+  "tasks": z.array(Task),
+});
+export type GetTasks = z.infer<typeof GetTasks>;
+
+// Name: TrashCollection
+export const TrashCollection = z.object({
+  // Name: TrashCollection.id
+  "id": z.string(),
+  // Name: TrashCollection.title
+  "title": z.string(),
+  // Name: TrashCollection.series
+  "series": z.string().nullable().optional(),
+  // Name: TrashCollection.volume
+  "volume": z.number().nullable().optional(),
+  // Name: TrashCollection.cover
+  "cover": Images.nullable().optional(),
+  // Name: TrashCollection.coverImageId
+  "coverImageId": z.string().nullable().optional(),
+  // Name: TrashCollection.customCover
+  "customCover": z.boolean(),
+  // Name: TrashCollection.release
+  "release": CollectionRelease.nullable().optional(),
+  // Name: TrashCollection.deleted
+  "deleted": z.number(),
+  // Name: TrashCollection.purgeAt
+  "purgeAt": z.number().nullable().optional(),
+});
+export type TrashCollection = z.infer<typeof TrashCollection>;
+
+// Name: GetTrash
+export const GetTrash = z.object({
+  // Name: GetTrash.page
+  "page": Page,
+  // Name: GetTrash.collections
+  "collections": z.array(TrashCollection),
+});
+export type GetTrash = z.infer<typeof GetTrash>;
+
+// Name: RunGC
+export const RunGC = z.object({
+  // Name: RunGC.dryRun
+  "dryRun": z.boolean(),
+  // Name: RunGC.orphanFiles
+  "orphanFiles": z.array(z.string()),
+  // Name: RunGC.danglingImages
+  "danglingImages": z.array(GCDanglingImage),
+  // Name: RunGC.unreferencedBlobs
+  "unreferencedBlobs": z.number(),
+  // Name: RunGC.freedBytes
+  "freedBytes": z.number(),
+});
+export type RunGC = z.infer<typeof RunGC>;
+
+// Name: RunGCBody
+export const RunGCBody = z.object({
+  // Name: RunGCBody.dryRun
+  "dryRun": z.boolean(),
+});
+export type RunGCBody = z.infer<typeof RunGCBody>;
+
+// Name: SetCollectionCoverBody
+export const SetCollectionCoverBody = z.object({
+  // Name: SetCollectionCoverBody.imageId
+  "imageId": z.string(),
+});
+export type SetCollectionCoverBody = z.infer<typeof SetCollectionCoverBody>;
+
 // Name: Signin
 export const Signin = z.object({
   // Name: Signin.token
@@ -103,3 +613,35 @@ export const SigninBody = z.object({
 });
 export type SigninBody = z.infer<typeof SigninBody>;
 
+// Name: SplitCollectionImage
+export const SplitCollectionImage = z.object({
+  // Name: SplitCollectionImage.images
+  "images": z.array(CollectionImage),
+});
+export type SplitCollectionImage = z.infer<typeof SplitCollectionImage>;
+
+// Name: UpdateReaderDefaultsBody
+export const UpdateReaderDefaultsBody = z.object({
+  // Name: UpdateReaderDefaultsBody.direction
+  "direction": z.string().nullable().optional(),
+  // Name: UpdateReaderDefaultsBody.layout
+  "layout": z.string().nullable().optional(),
+  // Name: UpdateReaderDefaultsBody.fit
+  "fit": z.string().nullable().optional(),
+});
+export type UpdateReaderDefaultsBody = z.infer<typeof UpdateReaderDefaultsBody>;
+
+// Name: UploadToCollection
+export const UploadToCollection = z.object({
+  // Name: UploadToCollection.jobIds
+  "jobIds": z.array(z.string()),
+});
+export type UploadToCollection = z.infer<typeof UploadToCollection>;
+
+// Name: VerifyImagesBody
+export const VerifyImagesBody = z.object({
+  // Name: VerifyImagesBody.full
+  "full": z.boolean(),
+});
+export type VerifyImagesBody = z.infer<typeof VerifyImagesBody>;
+